@@ -0,0 +1,66 @@
+package log
+
+import (
+	"runtime"
+	"strings"
+)
+
+// CallerConfig configures the caller field of entries
+type CallerConfig struct {
+	// Enabled adds caller field, e.g. "log/caller.go:42"
+	Enabled bool
+	// Func adds func field which is the full name of the calling function
+	Func bool
+	// Skip is the number of extra frames to skip.  It is useful when the logger is wrapped by another package.
+	Skip int
+}
+
+// SetCaller configures the caller field of entries.  The caller field is disabled by default.
+func (l *Logger) SetCaller(config CallerConfig) {
+	l.caller.Store(config)
+}
+
+func (l *Logger) callerConfig() CallerConfig {
+	return l.caller.Load().(CallerConfig)
+}
+
+// appendCaller appends the caller which is skip frames above runtime.Caller, or the caller of
+// e.pc when it is set
+func (e *Entry) appendCaller(config CallerConfig, names FieldNames, skip int) {
+	pc := e.pc
+	if pc == 0 {
+		pcs := [1]uintptr{}
+		if runtime.Callers(skip+1, pcs[:]) == 0 {
+			return
+		}
+		pc = pcs[0]
+	}
+	frame, _ := runtime.CallersFrames([]uintptr{pc}).Next()
+	if frame.PC == 0 {
+		return
+	}
+
+	e.buf = enc.AppendKey(e.buf, names.Caller)
+	e.buf = enc.AppendString(e.buf, trimCallerPath(frame.File))
+	e.buf = append(e.buf[:len(e.buf)-1], ':') // replace the closing quote
+	e.buf = enc.AppendInt(e.buf, frame.Line)
+	e.buf = append(e.buf, '"')
+
+	if config.Func && len(frame.Function) > 0 {
+		e.buf = enc.AppendKey(e.buf, names.Func)
+		e.buf = enc.AppendString(e.buf, frame.Function)
+	}
+}
+
+// trimCallerPath keeps the package directory and file name, e.g. "log/caller.go"
+func trimCallerPath(file string) string {
+	idx := strings.LastIndexByte(file, '/')
+	if idx < 0 {
+		return file
+	}
+	idx = strings.LastIndexByte(file[:idx], '/')
+	if idx < 0 {
+		return file
+	}
+	return file[idx+1:]
+}
@@ -0,0 +1,84 @@
+package log_test
+
+import (
+	"context"
+	"fmt"
+	"runtime"
+	"testing"
+
+	"github.com/jasonsoft/log/v2"
+	"github.com/jasonsoft/log/v2/handlers/memory"
+	"github.com/stretchr/testify/assert"
+)
+
+func line() int {
+	_, _, line, _ := runtime.Caller(1)
+	return line
+}
+
+func logHelper(c log.Context) {
+	c.CallerSkip(1).Info("from helper")
+}
+
+func TestCaller(t *testing.T) {
+	logger := log.New()
+	h := memory.New()
+	logger.AddHandler(h, log.AllLevels...)
+	logger.SetCaller(log.CallerConfig{Enabled: true})
+
+	caller := func(line int) string {
+		return fmt.Sprintf(`/caller_test.go:%d"`, line)
+	}
+
+	logger.Info("logger")
+	l := line() - 1
+	assert.Contains(t, string(h.Out), caller(l))
+
+	logger.Str("a", "b").Infof("context %s", "hello")
+	l = line() - 1
+	assert.Contains(t, string(h.Out), caller(l))
+
+	logger.FromContext(context.Background()).Warn("from context")
+	l = line() - 1
+	assert.Contains(t, string(h.Out), caller(l))
+
+	logHelper(logger.Str("a", "b"))
+	l = line() - 1
+	assert.Contains(t, string(h.Out), caller(l))
+
+	func() {
+		defer logger.Trace("trace").Stop()
+	}() // deferred functions are reported at the end of the function
+	l = line() - 1
+	assert.Contains(t, string(h.Out), caller(l))
+
+	e := logger.Str("a", "b").Trace("context trace")
+	e.Stop()
+	l = line() - 1
+	assert.Contains(t, string(h.Out), caller(l))
+
+	logger.SetCaller(log.CallerConfig{Enabled: true, Func: true})
+	logger.Debug("func")
+	l = line() - 1
+	assert.Contains(t, string(h.Out), caller(l)+`,"func":"github.com/jasonsoft/log/v2_test.TestCaller"`)
+
+	logger.SetCaller(log.CallerConfig{})
+	logger.Info("disabled")
+	assert.NotContains(t, string(h.Out), "caller")
+}
+
+func TestDefaultLoggerCaller(t *testing.T) {
+	log.RemoveAllHandlers()
+	h := memory.New()
+	log.AddHandler(h, log.AllLevels...)
+	log.SetCaller(log.CallerConfig{Enabled: true})
+	defer log.SetCaller(log.CallerConfig{})
+
+	log.Info("default")
+	l := line() - 1
+	assert.Contains(t, string(h.Out), fmt.Sprintf(`/caller_test.go:%d"`, l))
+
+	log.Str("a", "b").Debugf("default %s", "context")
+	l = line() - 1
+	assert.Contains(t, string(h.Out), fmt.Sprintf(`/caller_test.go:%d"`, l))
+}
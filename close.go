@@ -0,0 +1,212 @@
+package log
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+)
+
+// Closer is an interface that allow handlers to release their resources when the logger is closed.
+// The handler should write its buffered data before closing.
+type Closer interface {
+	Close() error
+}
+
+// exitTimeout is how long Fatal waits for the handlers to be flushed before exiting
+const exitTimeout = 5 * time.Second
+
+// Close removes all handlers from the logger, waits for the entries which are being written
+// and the removed handlers which are being flushed, then flushes and closes every handler.
+// The handlers are closed concurrently, and the errors of the handlers are aggregated into
+// one error.  If the context is done first, the handlers which haven't been closed report
+// the error of the context.
+func (l *Logger) Close(ctx context.Context) error {
+	l.rwMutex.Lock()
+	handles := l.handles
+	l.handles = []namedHandler{}
+	l.leveledHandlers = map[Level][]Handler{}
+	retired := l.updateHandlerSet()
+	flushes := l.flushes
+	l.flushes = nil
+	l.rwMutex.Unlock()
+
+	wait := func() {
+		retired.wait()
+		for _, f := range flushes {
+			<-f
+		}
+	}
+	return eachHandler(ctx, "close", handles, wait, closeHandler)
+}
+
+// flush flushes every handler without removing it, so the entries which are logged after
+// Fatal, e.g. when the exit func returns, are still written.
+func (l *Logger) flush(ctx context.Context) error {
+	l.rwMutex.RLock()
+	handles := l.handles
+	l.rwMutex.RUnlock()
+
+	return eachHandler(ctx, "flush", handles, func() {}, flushHandler)
+}
+
+// eachHandler calls fn with every handler concurrently after wait returns, and aggregates
+// the errors of the op.  If the context is done first, the handlers which haven't been done report
+// the error of the context.
+func eachHandler(ctx context.Context, op string, handles []namedHandler, wait func(), fn func(Handler) error) error {
+	type result struct {
+		idx int
+		err error
+	}
+	results := make(chan result, len(handles))
+	waited := make(chan struct{})
+	go func() {
+		wait()
+		close(waited)
+		for i, h := range handles {
+			go func(i int, h Handler) {
+				results <- result{idx: i, err: fn(h)}
+			}(i, h.handler)
+		}
+	}()
+
+	errs := handlerErrors{op: op}
+	done := make([]bool, len(handles))
+	canceled := func() error {
+		for i, h := range handles {
+			if !done[i] {
+				errs.errs = append(errs.errs, handlerError{handler: h, err: ctx.Err()})
+			}
+		}
+		if len(errs.errs) == 0 {
+			return ctx.Err()
+		}
+		return errs
+	}
+
+	select {
+	case <-waited:
+	case <-ctx.Done():
+		return canceled()
+	}
+	for n := 0; n < len(handles); n++ {
+		select {
+		case r := <-results:
+			done[r.idx] = true
+			if r.err != nil {
+				errs.errs = append(errs.errs, handlerError{handler: handles[r.idx], err: r.err})
+			}
+		case <-ctx.Done():
+			return canceled()
+		}
+	}
+
+	if len(errs.errs) > 0 {
+		return errs
+	}
+	return nil
+}
+
+// AddExitHook adds a func which is called before Fatal exits the process.  The hooks are
+// called in order, then the handlers are flushed.
+func (l *Logger) AddExitHook(hook func()) {
+	l.rwMutex.Lock()
+	defer l.rwMutex.Unlock()
+
+	l.exitHooks = append(l.exitHooks, hook)
+}
+
+// exit calls the exit hooks and flushes the handlers, then exits the process with the code.
+// The handlers are flushed rather than closed: they are still registered and open, so an
+// exit func which doesn't exit the process can keep logging.  Call Close to release them.
+func (l *Logger) exit(code int) {
+	l.rwMutex.RLock()
+	hooks := l.exitHooks
+	l.rwMutex.RUnlock()
+
+	for _, hook := range hooks {
+		hook()
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), exitTimeout)
+	err := l.flush(ctx)
+	cancel()
+	if err != nil {
+		ReportError("%v", err)
+	}
+
+	l.exitFunc.Load().(func(code int))(code)
+}
+
+// closeHandler flushes and closes the handler.  The handler is still closed if flushing fails.
+func closeHandler(h Handler) error {
+	err := flushHandler(h)
+
+	closer, ok := h.(Closer)
+	if ok {
+		closeErr := closer.Close()
+		if err == nil {
+			err = closeErr
+		}
+	}
+	return err
+}
+
+type handlerError struct {
+	handler namedHandler
+	err     error
+}
+
+func (e handlerError) Error() string {
+	if len(e.handler.name) > 0 {
+		return fmt.Sprintf("%s: %v", e.handler.name, e.err)
+	}
+	return fmt.Sprintf("%T: %v", e.handler.handler, e.err)
+}
+
+func (e handlerError) Unwrap() error {
+	return e.err
+}
+
+// handlerErrors is the aggregated errors of the handlers which fail to be closed or flushed
+type handlerErrors struct {
+	op   string
+	errs []handlerError
+}
+
+func (e handlerErrors) Error() string {
+	msgs := make([]string, len(e.errs))
+	for i, err := range e.errs {
+		msgs[i] = err.Error()
+	}
+	return "log: " + e.op + " handlers: " + strings.Join(msgs, "; ")
+}
+
+// Unwrap returns the errors of the handlers.  Is and As match any of them as well, e.g.
+// context.DeadlineExceeded, because errors.Is doesn't follow Unwrap() []error before Go 1.20.
+func (e handlerErrors) Unwrap() []error {
+	errs := make([]error, len(e.errs))
+	for i, err := range e.errs {
+		errs[i] = err
+	}
+	return errs
+}
+
+func (e handlerErrors) Is(target error) bool {
+	for _, err := range e.errs {
+		if errors.Is(err, target) {
+			return true
+		}
+	}
+	return false
+}
+
+func (e handlerErrors) As(target interface{}) bool {
+	for _, err := range e.errs {
+		if errors.As(err, target) {
+			return true
+		}
+	}
+	return false
+}
@@ -3,16 +3,21 @@ package log
 import (
 	"context"
 	"fmt"
+	"net"
 	"time"
 )
 
 // Context use for meta data
 type Context struct {
-	logger *logger
-	buf    []byte
+	logger     *Logger
+	name       string
+	callerSkip int
+	span       SpanContext
+	errStack   []uintptr
+	buf        []byte
 }
 
-func newContext(l *logger) Context {
+func newContext(l *Logger) Context {
 	c := Context{
 		logger: l,
 	}
@@ -27,6 +32,23 @@ func newContext(l *logger) Context {
 	return c
 }
 
+// getLogger returns the logger of the context, or the default logger for a zero Context
+func (c Context) getLogger() *Logger {
+	if c.logger == nil {
+		return _logger
+	}
+	return c.logger
+}
+
+func (c Context) newEntry() *Entry {
+	e := newEntry(c.getLogger(), c.buf)
+	e.name = c.name
+	e.callerSkip = 1 + c.callerSkip
+	e.span = c.span
+	e.errStack = c.errStack
+	return e
+}
+
 func copyBytes(src []byte) []byte {
 	newBuf := make([]byte, len(src))
 	if len(src) > 0 {
@@ -35,83 +57,102 @@ func copyBytes(src []byte) []byte {
 	return newBuf
 }
 
+// Named returns a child context whose name is appended to the current name with a dot,
+// e.g. "db" becomes "db.pool".  The name is added as logger field and the minimum level
+// of the context can be configured by SetFilter.
+func (c Context) Named(name string) Context {
+	if len(c.name) > 0 {
+		name = c.name + "." + name
+	}
+	c.name = name
+	return c
+}
+
+// CallerSkip returns a context which skips more frames when it reports the caller.  It is
+// useful for helper functions which log on behalf of their callers.
+func (c Context) CallerSkip(skip int) Context {
+	c.callerSkip += skip
+	return c
+}
+
 // SaveToDefault save the current context to default logger and these context to be printed with every entry
 func (c Context) SaveToDefault() {
-	c.logger.rwMutex.Lock()
-	defer c.logger.rwMutex.Unlock()
+	l := c.getLogger()
+	l.rwMutex.Lock()
+	defer l.rwMutex.Unlock()
 
-	c.logger.buf = copyBytes(c.buf)
+	l.buf = copyBytes(c.buf)
 }
 
 // Debug level formatted message.
 func (c Context) Debug(msg string) {
-	e := newEntry(_logger, c.buf)
+	e := c.newEntry()
 	e.Debug(msg)
 }
 
 // Debugf level formatted message.
 func (c Context) Debugf(msg string, v ...interface{}) {
-	e := newEntry(_logger, c.buf)
+	e := c.newEntry()
 	e.Debugf(msg, v...)
 }
 
 // Info level formatted message.
 func (c Context) Info(msg string) {
-	e := newEntry(_logger, c.buf)
+	e := c.newEntry()
 	e.Info(msg)
 }
 
 // Infof level formatted message.
 func (c Context) Infof(msg string, v ...interface{}) {
-	e := newEntry(_logger, c.buf)
+	e := c.newEntry()
 	e.Infof(msg, v...)
 }
 
 // Warn level formatted message.
 func (c Context) Warn(msg string) {
-	e := newEntry(_logger, c.buf)
+	e := c.newEntry()
 	e.Warn(msg)
 }
 
 // Warnf level formatted message.
 func (c Context) Warnf(msg string, v ...interface{}) {
-	e := newEntry(_logger, c.buf)
+	e := c.newEntry()
 	e.Warnf(msg, v...)
 }
 
 // Error level formatted message
 func (c Context) Error(msg string) {
-	e := newEntry(_logger, c.buf)
+	e := c.newEntry()
 	e.Error(msg)
 }
 
 // Errorf level formatted message
 func (c Context) Errorf(msg string, v ...interface{}) {
-	e := newEntry(_logger, c.buf)
+	e := c.newEntry()
 	e.Errorf(msg, v...)
 }
 
 // Panic level formatted message
 func (c Context) Panic(msg string) {
-	e := newEntry(_logger, c.buf)
+	e := c.newEntry()
 	e.Panic(msg)
 }
 
 // Panicf level formatted message
 func (c Context) Panicf(msg string, v ...interface{}) {
-	e := newEntry(_logger, c.buf)
+	e := c.newEntry()
 	e.Panicf(msg, v...)
 }
 
 // Fatal level formatted message
 func (c Context) Fatal(msg string) {
-	e := newEntry(_logger, c.buf)
+	e := c.newEntry()
 	e.Fatal(msg)
 }
 
 // Fatalf level formatted message
 func (c Context) Fatalf(msg string, v ...interface{}) {
-	e := newEntry(_logger, c.buf)
+	e := c.newEntry()
 	e.Fatalf(msg, v...)
 }
 
@@ -246,16 +287,19 @@ func (c Context) Float64(key string, val float64) Context {
 // Err add error field to current context
 func (c Context) Err(err error) Context {
 	c.buf = copyBytes(c.buf)
-	c.buf = enc.AppendKey(c.buf, "error")
-	c.buf = enc.AppendString(c.buf, fmt.Sprintf("%+v", err))
+	c.buf = enc.AppendKey(c.buf, c.getLogger().FieldNames().Error)
+	c.buf = c.getLogger().appendError(c.buf, err)
+	if stack := errorStack(err); stack != nil {
+		c.errStack = stack
+	}
 	return c
 }
 
 // StackTrace adds stack_trace field to the current context
 func (c Context) StackTrace() Context {
 	c.buf = copyBytes(c.buf)
-	c.buf = enc.AppendKey(c.buf, "stack_trace")
-	c.buf = enc.AppendString(c.buf, getStackTrace())
+	c.buf = enc.AppendKey(c.buf, c.getLogger().FieldNames().Stack)
+	c.buf = c.getLogger().appendStackTrace(c.buf, c.errStack)
 	return c
 }
 
@@ -283,6 +327,178 @@ func (c Context) Interface(key string, val interface{}) Context {
 	return c
 }
 
+// Dur adds Duration field to current context
+func (c Context) Dur(key string, val time.Duration) Context {
+	c.buf = copyBytes(c.buf)
+	c.buf = enc.AppendKey(c.buf, key)
+	c.buf = enc.AppendDuration(c.buf, val, time.Millisecond, false)
+	return c
+}
+
+// Stringer adds the string of the Stringer field to current context, or null if it is nil
+func (c Context) Stringer(key string, val fmt.Stringer) Context {
+	c.buf = copyBytes(c.buf)
+	c.buf = enc.AppendKey(c.buf, key)
+	if val == nil {
+		c.buf = enc.AppendNil(c.buf)
+	} else {
+		c.buf = enc.AppendString(c.buf, val.String())
+	}
+	return c
+}
+
+// Bools add bool array field to current context
+func (c Context) Bools(key string, val []bool) Context {
+	c.buf = copyBytes(c.buf)
+	c.buf = enc.AppendKey(c.buf, key)
+	c.buf = enc.AppendBools(c.buf, val)
+	return c
+}
+
+// Ints8 add Int8 array field to current context
+func (c Context) Ints8(key string, val []int8) Context {
+	c.buf = copyBytes(c.buf)
+	c.buf = enc.AppendKey(c.buf, key)
+	c.buf = enc.AppendInts8(c.buf, val)
+	return c
+}
+
+// Ints16 add Int16 array field to current context
+func (c Context) Ints16(key string, val []int16) Context {
+	c.buf = copyBytes(c.buf)
+	c.buf = enc.AppendKey(c.buf, key)
+	c.buf = enc.AppendInts16(c.buf, val)
+	return c
+}
+
+// Ints32 add Int32 array field to current context
+func (c Context) Ints32(key string, val []int32) Context {
+	c.buf = copyBytes(c.buf)
+	c.buf = enc.AppendKey(c.buf, key)
+	c.buf = enc.AppendInts32(c.buf, val)
+	return c
+}
+
+// Ints64 add Int64 array field to current context
+func (c Context) Ints64(key string, val []int64) Context {
+	c.buf = copyBytes(c.buf)
+	c.buf = enc.AppendKey(c.buf, key)
+	c.buf = enc.AppendInts64(c.buf, val)
+	return c
+}
+
+// Uints add Uint array field to current context
+func (c Context) Uints(key string, val []uint) Context {
+	c.buf = copyBytes(c.buf)
+	c.buf = enc.AppendKey(c.buf, key)
+	c.buf = enc.AppendUints(c.buf, val)
+	return c
+}
+
+// Uints8 add Uint8 array field to current context
+func (c Context) Uints8(key string, val []uint8) Context {
+	c.buf = copyBytes(c.buf)
+	c.buf = enc.AppendKey(c.buf, key)
+	c.buf = enc.AppendUints8(c.buf, val)
+	return c
+}
+
+// Uints16 add Uint16 array field to current context
+func (c Context) Uints16(key string, val []uint16) Context {
+	c.buf = copyBytes(c.buf)
+	c.buf = enc.AppendKey(c.buf, key)
+	c.buf = enc.AppendUints16(c.buf, val)
+	return c
+}
+
+// Uints32 add Uint32 array field to current context
+func (c Context) Uints32(key string, val []uint32) Context {
+	c.buf = copyBytes(c.buf)
+	c.buf = enc.AppendKey(c.buf, key)
+	c.buf = enc.AppendUints32(c.buf, val)
+	return c
+}
+
+// Uints64 add Uint64 array field to current context
+func (c Context) Uints64(key string, val []uint64) Context {
+	c.buf = copyBytes(c.buf)
+	c.buf = enc.AppendKey(c.buf, key)
+	c.buf = enc.AppendUints64(c.buf, val)
+	return c
+}
+
+// Floats32 add Float32 array field to current context
+func (c Context) Floats32(key string, val []float32) Context {
+	c.buf = copyBytes(c.buf)
+	c.buf = enc.AppendKey(c.buf, key)
+	c.buf = enc.AppendFloats32(c.buf, val)
+	return c
+}
+
+// Floats64 add Float64 array field to current context
+func (c Context) Floats64(key string, val []float64) Context {
+	c.buf = copyBytes(c.buf)
+	c.buf = enc.AppendKey(c.buf, key)
+	c.buf = enc.AppendFloats64(c.buf, val)
+	return c
+}
+
+// Bytes add bytes field as a string to current context
+func (c Context) Bytes(key string, val []byte) Context {
+	c.buf = copyBytes(c.buf)
+	c.buf = enc.AppendKey(c.buf, key)
+	c.buf = enc.AppendBytes(c.buf, val)
+	return c
+}
+
+// Hex add bytes field as a hex string to current context
+func (c Context) Hex(key string, val []byte) Context {
+	c.buf = copyBytes(c.buf)
+	c.buf = enc.AppendKey(c.buf, key)
+	c.buf = enc.AppendHex(c.buf, val)
+	return c
+}
+
+// Durs add Duration array field in milliseconds to current context
+func (c Context) Durs(key string, val []time.Duration) Context {
+	c.buf = copyBytes(c.buf)
+	c.buf = enc.AppendKey(c.buf, key)
+	c.buf = enc.AppendDurations(c.buf, val, time.Millisecond, false)
+	return c
+}
+
+// IPAddr add IPv4 or IPv6 address field to current context
+func (c Context) IPAddr(key string, val net.IP) Context {
+	c.buf = copyBytes(c.buf)
+	c.buf = enc.AppendKey(c.buf, key)
+	c.buf = enc.AppendIPAddr(c.buf, val)
+	return c
+}
+
+// IPPrefix add IPv4 or IPv6 prefix (address and mask) field to current context
+func (c Context) IPPrefix(key string, val net.IPNet) Context {
+	c.buf = copyBytes(c.buf)
+	c.buf = enc.AppendKey(c.buf, key)
+	c.buf = enc.AppendIPPrefix(c.buf, val)
+	return c
+}
+
+// MACAddr add MAC address field to current context
+func (c Context) MACAddr(key string, val net.HardwareAddr) Context {
+	c.buf = copyBytes(c.buf)
+	c.buf = enc.AppendKey(c.buf, key)
+	c.buf = enc.AppendMACAddr(c.buf, val)
+	return c
+}
+
+// RawJSON add already encoded JSON field to current context.  The value must be valid JSON
+func (c Context) RawJSON(key string, val []byte) Context {
+	c.buf = copyBytes(c.buf)
+	c.buf = enc.AppendKey(c.buf, key)
+	c.buf = append(c.buf, val...)
+	return c
+}
+
 // WithContext return a new context with a log context value
 func (c Context) WithContext(ctx context.Context) context.Context {
 	return newStdContext(ctx, c)
@@ -0,0 +1,45 @@
+package log
+
+import (
+	"context"
+)
+
+// ContextExtractor pulls values out of ctx and adds them to the log context as fields,
+// e.g. the request ID which is set by a middleware
+type ContextExtractor func(ctx context.Context, c Context) Context
+
+// AddContextExtractor adds an extractor which is called by Ctx
+func (l *Logger) AddContextExtractor(extractor ContextExtractor) {
+	l.rwMutex.Lock()
+	defer l.rwMutex.Unlock()
+
+	extractors := l.extractors.Load().([]ContextExtractor)
+	newExtractors := make([]ContextExtractor, 0, len(extractors)+1)
+	newExtractors = append(newExtractors, extractors...)
+	l.extractors.Store(append(newExtractors, extractor))
+}
+
+// Ctx returns the log context stored in ctx, and the fields of the extractors are added to it.
+// The extractors are called in the order they are added.  If the trace provider supplies the
+// span context of ctx, the entries have the trace_id, span_id and trace_flags fields.
+func (l *Logger) Ctx(ctx context.Context) Context {
+	c := l.FromContext(ctx)
+	if sc, ok := l.tracingConfig().Provider.SpanContext(ctx); ok {
+		c.span = sc
+	}
+	for _, extractor := range l.extractors.Load().([]ContextExtractor) {
+		c = extractor(ctx, c)
+	}
+	return c
+}
+
+// AddContextExtractor adds an extractor which is called by Ctx of the default logger
+func AddContextExtractor(extractor ContextExtractor) {
+	_logger.AddContextExtractor(extractor)
+}
+
+// Ctx returns the log context stored in ctx with the fields of the extractors of the default logger,
+// e.g. log.Ctx(ctx).Info("hello")
+func Ctx(ctx context.Context) Context {
+	return _logger.Ctx(ctx)
+}
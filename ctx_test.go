@@ -0,0 +1,41 @@
+package log_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/jasonsoft/log/v2"
+	"github.com/jasonsoft/log/v2/handlers/memory"
+	"github.com/stretchr/testify/assert"
+)
+
+type tenantKey struct{}
+
+func TestCtx(t *testing.T) {
+	logger := log.New()
+	h := memory.New()
+	logger.AddHandler(h, log.AllLevels...)
+
+	logger.AddContextExtractor(func(ctx context.Context, c log.Context) log.Context {
+		if tenant, ok := ctx.Value(tenantKey{}).(string); ok {
+			c = c.Str("tenant", tenant)
+		}
+		return c
+	})
+
+	ctx := context.WithValue(context.Background(), tenantKey{}, "a")
+	logger.Ctx(ctx).Info("hello")
+	assert.Equal(t, `{"tenant":"a","level":"INFO","msg":"hello"}`+"\n", string(h.Out))
+
+	// the fields of the stored context come first
+	ctx = logger.Str("request_id", "abc").WithContext(ctx)
+	logger.Ctx(ctx).Info("hello")
+	assert.Equal(t, `{"request_id":"abc","tenant":"a","level":"INFO","msg":"hello"}`+"\n", string(h.Out))
+
+	// the stored context isn't changed by the extractors
+	logger.FromContext(ctx).Info("hello")
+	assert.Equal(t, `{"request_id":"abc","level":"INFO","msg":"hello"}`+"\n", string(h.Out))
+
+	logger.Ctx(context.Background()).Info("hello")
+	assert.Equal(t, `{"level":"INFO","msg":"hello"}`+"\n", string(h.Out))
+}
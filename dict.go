@@ -0,0 +1,202 @@
+package log
+
+import (
+	"sync"
+	"time"
+)
+
+var dictPool = &sync.Pool{
+	New: func() interface{} {
+		return &Dict{}
+	},
+}
+
+// Dict builds a nested JSON object.  It writes into the buffer of the entry or context
+// directly, so it must not be used after the func which receives it returns.
+type Dict struct {
+	buf []byte
+}
+
+// appendDict appends the nested object which is built by fn to buf
+func appendDict(buf []byte, key string, fn func(d *Dict)) []byte {
+	d := dictPool.Get().(*Dict)
+	d.buf = enc.AppendKey(buf, key)
+	d.buf = enc.AppendBeginMarker(d.buf)
+	fn(d)
+	buf = enc.AppendEndMarker(d.buf)
+	d.buf = nil
+	dictPool.Put(d)
+	return buf
+}
+
+// Dict adds a nested object field to current entry, e.g.
+//
+//	log.Str("app", "santa").Dict("user", func(d *log.Dict) {
+//		d.Str("name", "john").Int("age", 18)
+//	}).Info("hello")
+func (e *Entry) Dict(key string, fn func(d *Dict)) *Entry {
+	if e == nil {
+		return e
+	}
+	e.buf = appendDict(e.buf, key, fn)
+	return e
+}
+
+// Dict adds a nested object field to current context
+func (c Context) Dict(key string, fn func(d *Dict)) Context {
+	c.buf = copyBytes(c.buf)
+	c.buf = appendDict(c.buf, key, fn)
+	return c
+}
+
+// Dict adds a nested object field to current context
+func (l *Logger) Dict(key string, fn func(d *Dict)) Context {
+	c := newContext(l)
+	return c.Dict(key, fn)
+}
+
+// Dict adds a nested object field
+func (d *Dict) Dict(key string, fn func(d *Dict)) *Dict {
+	d.buf = appendDict(d.buf, key, fn)
+	return d
+}
+
+// Str adds string field
+func (d *Dict) Str(key string, val string) *Dict {
+	d.buf = enc.AppendKey(d.buf, key)
+	d.buf = enc.AppendString(d.buf, val)
+	return d
+}
+
+// Strs adds string array field
+func (d *Dict) Strs(key string, val []string) *Dict {
+	d.buf = enc.AppendKey(d.buf, key)
+	d.buf = enc.AppendStrings(d.buf, val)
+	return d
+}
+
+// Bool adds bool field
+func (d *Dict) Bool(key string, val bool) *Dict {
+	d.buf = enc.AppendKey(d.buf, key)
+	d.buf = enc.AppendBool(d.buf, val)
+	return d
+}
+
+// Int adds Int field
+func (d *Dict) Int(key string, val int) *Dict {
+	d.buf = enc.AppendKey(d.buf, key)
+	d.buf = enc.AppendInt(d.buf, val)
+	return d
+}
+
+// Ints adds Int array field
+func (d *Dict) Ints(key string, val []int) *Dict {
+	d.buf = enc.AppendKey(d.buf, key)
+	d.buf = enc.AppendInts(d.buf, val)
+	return d
+}
+
+// Int8 adds Int8 field
+func (d *Dict) Int8(key string, val int8) *Dict {
+	d.buf = enc.AppendKey(d.buf, key)
+	d.buf = enc.AppendInt8(d.buf, val)
+	return d
+}
+
+// Int16 adds Int16 field
+func (d *Dict) Int16(key string, val int16) *Dict {
+	d.buf = enc.AppendKey(d.buf, key)
+	d.buf = enc.AppendInt16(d.buf, val)
+	return d
+}
+
+// Int32 adds Int32 field
+func (d *Dict) Int32(key string, val int32) *Dict {
+	d.buf = enc.AppendKey(d.buf, key)
+	d.buf = enc.AppendInt32(d.buf, val)
+	return d
+}
+
+// Int64 adds Int64 field
+func (d *Dict) Int64(key string, val int64) *Dict {
+	d.buf = enc.AppendKey(d.buf, key)
+	d.buf = enc.AppendInt64(d.buf, val)
+	return d
+}
+
+// Uint adds Uint field
+func (d *Dict) Uint(key string, val uint) *Dict {
+	d.buf = enc.AppendKey(d.buf, key)
+	d.buf = enc.AppendUint(d.buf, val)
+	return d
+}
+
+// Uint8 adds Uint8 field
+func (d *Dict) Uint8(key string, val uint8) *Dict {
+	d.buf = enc.AppendKey(d.buf, key)
+	d.buf = enc.AppendUint8(d.buf, val)
+	return d
+}
+
+// Uint16 adds Uint16 field
+func (d *Dict) Uint16(key string, val uint16) *Dict {
+	d.buf = enc.AppendKey(d.buf, key)
+	d.buf = enc.AppendUint16(d.buf, val)
+	return d
+}
+
+// Uint32 adds Uint32 field
+func (d *Dict) Uint32(key string, val uint32) *Dict {
+	d.buf = enc.AppendKey(d.buf, key)
+	d.buf = enc.AppendUint32(d.buf, val)
+	return d
+}
+
+// Uint64 adds Uint64 field
+func (d *Dict) Uint64(key string, val uint64) *Dict {
+	d.buf = enc.AppendKey(d.buf, key)
+	d.buf = enc.AppendUint64(d.buf, val)
+	return d
+}
+
+// Float32 adds Float32 field
+func (d *Dict) Float32(key string, val float32) *Dict {
+	d.buf = enc.AppendKey(d.buf, key)
+	d.buf = enc.AppendFloat32(d.buf, val)
+	return d
+}
+
+// Float64 adds Float64 field
+func (d *Dict) Float64(key string, val float64) *Dict {
+	d.buf = enc.AppendKey(d.buf, key)
+	d.buf = enc.AppendFloat64(d.buf, val)
+	return d
+}
+
+// Time adds Time field
+func (d *Dict) Time(key string, val time.Time) *Dict {
+	d.buf = enc.AppendKey(d.buf, key)
+	d.buf = enc.AppendTime(d.buf, val, time.RFC3339)
+	return d
+}
+
+// Times adds Time array field
+func (d *Dict) Times(key string, val []time.Time) *Dict {
+	d.buf = enc.AppendKey(d.buf, key)
+	d.buf = enc.AppendTimes(d.buf, val, time.RFC3339)
+	return d
+}
+
+// Dur adds Duration field in milliseconds
+func (d *Dict) Dur(key string, val time.Duration) *Dict {
+	d.buf = enc.AppendKey(d.buf, key)
+	d.buf = enc.AppendDuration(d.buf, val, time.Millisecond, false)
+	return d
+}
+
+// Interface adds the field key with i marshaled using reflection.
+func (d *Dict) Interface(key string, val interface{}) *Dict {
+	d.buf = enc.AppendKey(d.buf, key)
+	d.buf = enc.AppendInterface(d.buf, val)
+	return d
+}
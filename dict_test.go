@@ -0,0 +1,34 @@
+package log_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/jasonsoft/log/v2"
+	"github.com/jasonsoft/log/v2/handlers/memory"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDict(t *testing.T) {
+	logger := log.New()
+	h := memory.New()
+	logger.AddHandler(h, log.AllLevels...)
+
+	c := logger.Str("app", "santa").Dict("user", func(d *log.Dict) {
+		d.Str("name", "john").Int("age", 18).Dict("geo", func(d *log.Dict) {
+			d.Str("city", "taipei").Float64("lat", 25.03)
+		})
+	})
+
+	c.Info("hello")
+	assert.Equal(t, `{"app":"santa","user":{"name":"john","age":18,"geo":{"city":"taipei","lat":25.03}},"level":"INFO","msg":"hello"}`+"\n", string(h.Out))
+
+	// the context isn't changed by the entries
+	c.Dict("req", func(d *log.Dict) {
+		d.Dur("took", 1500*time.Millisecond).Bool("ok", true).Strs("tags", []string{"a"})
+	}).Info("world")
+	assert.Equal(t, `{"app":"santa","user":{"name":"john","age":18,"geo":{"city":"taipei","lat":25.03}},"req":{"took":1500,"ok":true,"tags":["a"]},"level":"INFO","msg":"world"}`+"\n", string(h.Out))
+
+	logger.Dict("empty", func(d *log.Dict) {}).Info("empty")
+	assert.Equal(t, `{"empty":{},"level":"INFO","msg":"empty"}`+"\n", string(h.Out))
+}
@@ -1,9 +1,9 @@
 package log
 
 import (
+	"errors"
 	"fmt"
-	stdlog "log"
-	"os"
+	"net"
 	"sync"
 	"time"
 
@@ -22,23 +22,37 @@ var enc = json.Encoder{}
 
 // Entry defines a single log entry
 type Entry struct {
-	logger *logger
-	start  time.Time
-	buf    []byte
+	logger     *Logger
+	name       string
+	callerSkip int
+	pc         uintptr // the caller's program counter, which is used instead of callerSkip when it isn't 0
+	span       SpanContext
+	parentSpan [8]byte
+	errStack   []uintptr
+	start      time.Time
+	buf        []byte
 
 	Level   Level  `json:"level"`
 	Message string `json:"message"`
 }
 
-func newEntry(l *logger, buf []byte) *Entry {
+func newEntry(l *Logger, buf []byte) *Entry {
 	e := entryPool.Get().(*Entry)
 	e.logger = l
+	e.name = ""
+	e.callerSkip = 0
+	e.pc = 0
+	e.span = SpanContext{}
+	e.parentSpan = [8]byte{}
+	e.errStack = nil
 
 	if buf == nil {
 		e.buf = e.buf[:0]
 		e.buf = enc.AppendBeginMarker(e.buf)
 	} else {
-		e.buf = buf // race condition here because it use context's buf.  However, we create new buf within handler func
+		// copy the context's buf; otherwise, the entry writes into the context's buf and
+		// the pooled entry will overwrite it when it is reused.
+		e.buf = append(e.buf[:0], buf...)
 	}
 
 	return e
@@ -61,18 +75,15 @@ func putEntry(e *Entry) {
 func copyEntry(e *Entry) *Entry {
 	newEntry := entryPool.Get().(*Entry)
 
-	if len(e.buf) > cap(newEntry.buf) {
-		// append will auto increase slice's capacity  when needed
-		newEntry.buf = e.buf[:0]
-		newEntry.buf = append(newEntry.buf, e.buf...)
-	} else {
-		// Copy returns the number of elements copied, which will be the minimum of len(src) and len(dst).
-		// https://stackoverflow.com/questions/30182538/why-cant-i-duplicate-a-slice-with-copy
-		newEntry.buf = newEntry.buf[:len(e.buf)]
-		copy(newEntry.buf, e.buf)
-	}
+	// copy into the entry's own buffer, which grows when needed; otherwise, two pooled entries
+	// share one backing array
+	newEntry.buf = append(newEntry.buf[:0], e.buf...)
 
 	newEntry.logger = e.logger
+	newEntry.name = e.name
+	newEntry.span = e.span
+	newEntry.parentSpan = e.parentSpan
+	newEntry.errStack = e.errStack
 	newEntry.start = e.start
 	newEntry.Level = e.Level
 	newEntry.Message = e.Message
@@ -84,14 +95,15 @@ func copyEntry(e *Entry) *Entry {
 func (e *Entry) Trace(msg string) *Entry {
 	e.Level = InfoLevel
 	e.Message = msg
-	e.start = time.Now().UTC()
+	e.start = e.logger.now().UTC()
+	e.startChildSpan()
 	return e
 }
 
 // Stop should be used with Trace, to fire off the completion message. When
 // an `err` is passed the "error" field is set, and the log level is error.
 func (e *Entry) Stop() {
-	e = e.Dur("duration", time.Since(e.start))
+	e = e.Dur(e.logger.FieldNames().Duration, e.logger.now().Sub(e.start))
 	handler(e)
 }
 
@@ -172,8 +184,10 @@ func (e *Entry) Panic(msg string) {
 		e = e.StackTrace()
 	}
 
+	// the entry is put back to the pool after writing, so we keep what we need
+	logger, err := e.logger, e.panicError()
 	handler(e)
-	panic(msg)
+	logger.panic(err)
 }
 
 // Panicf level message.
@@ -185,8 +199,10 @@ func (e *Entry) Panicf(msg string, v ...interface{}) {
 		e = e.StackTrace()
 	}
 
+	// the entry is put back to the pool after writing, so we keep what we need
+	logger, err := e.logger, e.panicError()
 	handler(e)
-	panic(msg)
+	logger.panic(err)
 }
 
 // Fatal level message.
@@ -197,8 +213,9 @@ func (e *Entry) Fatal(msg string) {
 	if AutoStaceTrace {
 		e = e.StackTrace()
 	}
+	logger := e.logger
 	handler(e)
-	os.Exit(1)
+	logger.exit(1)
 }
 
 // Fatalf level message.
@@ -209,8 +226,9 @@ func (e *Entry) Fatalf(msg string, v ...interface{}) {
 	if AutoStaceTrace {
 		e = e.StackTrace()
 	}
+	logger := e.logger
 	handler(e)
-	os.Exit(1)
+	logger.exit(1)
 }
 
 // Str add string field to current entry
@@ -413,47 +431,297 @@ func (e *Entry) Interface(key string, val interface{}) *Entry {
 	return e
 }
 
+// Stringer adds the string of the Stringer field to current entry, or null if it is nil
+func (e *Entry) Stringer(key string, val fmt.Stringer) *Entry {
+	if e == nil {
+		return e
+	}
+	e.buf = enc.AppendKey(e.buf, key)
+	if val == nil {
+		e.buf = enc.AppendNil(e.buf)
+	} else {
+		e.buf = enc.AppendString(e.buf, val.String())
+	}
+	return e
+}
+
+// Bools add bool array field to current entry
+func (e *Entry) Bools(key string, val []bool) *Entry {
+	if e == nil {
+		return e
+	}
+	e.buf = enc.AppendKey(e.buf, key)
+	e.buf = enc.AppendBools(e.buf, val)
+	return e
+}
+
+// Ints8 add Int8 array field to current entry
+func (e *Entry) Ints8(key string, val []int8) *Entry {
+	if e == nil {
+		return e
+	}
+	e.buf = enc.AppendKey(e.buf, key)
+	e.buf = enc.AppendInts8(e.buf, val)
+	return e
+}
+
+// Ints16 add Int16 array field to current entry
+func (e *Entry) Ints16(key string, val []int16) *Entry {
+	if e == nil {
+		return e
+	}
+	e.buf = enc.AppendKey(e.buf, key)
+	e.buf = enc.AppendInts16(e.buf, val)
+	return e
+}
+
+// Ints32 add Int32 array field to current entry
+func (e *Entry) Ints32(key string, val []int32) *Entry {
+	if e == nil {
+		return e
+	}
+	e.buf = enc.AppendKey(e.buf, key)
+	e.buf = enc.AppendInts32(e.buf, val)
+	return e
+}
+
+// Ints64 add Int64 array field to current entry
+func (e *Entry) Ints64(key string, val []int64) *Entry {
+	if e == nil {
+		return e
+	}
+	e.buf = enc.AppendKey(e.buf, key)
+	e.buf = enc.AppendInts64(e.buf, val)
+	return e
+}
+
+// Uints add Uint array field to current entry
+func (e *Entry) Uints(key string, val []uint) *Entry {
+	if e == nil {
+		return e
+	}
+	e.buf = enc.AppendKey(e.buf, key)
+	e.buf = enc.AppendUints(e.buf, val)
+	return e
+}
+
+// Uints8 add Uint8 array field to current entry
+func (e *Entry) Uints8(key string, val []uint8) *Entry {
+	if e == nil {
+		return e
+	}
+	e.buf = enc.AppendKey(e.buf, key)
+	e.buf = enc.AppendUints8(e.buf, val)
+	return e
+}
+
+// Uints16 add Uint16 array field to current entry
+func (e *Entry) Uints16(key string, val []uint16) *Entry {
+	if e == nil {
+		return e
+	}
+	e.buf = enc.AppendKey(e.buf, key)
+	e.buf = enc.AppendUints16(e.buf, val)
+	return e
+}
+
+// Uints32 add Uint32 array field to current entry
+func (e *Entry) Uints32(key string, val []uint32) *Entry {
+	if e == nil {
+		return e
+	}
+	e.buf = enc.AppendKey(e.buf, key)
+	e.buf = enc.AppendUints32(e.buf, val)
+	return e
+}
+
+// Uints64 add Uint64 array field to current entry
+func (e *Entry) Uints64(key string, val []uint64) *Entry {
+	if e == nil {
+		return e
+	}
+	e.buf = enc.AppendKey(e.buf, key)
+	e.buf = enc.AppendUints64(e.buf, val)
+	return e
+}
+
+// Floats32 add Float32 array field to current entry
+func (e *Entry) Floats32(key string, val []float32) *Entry {
+	if e == nil {
+		return e
+	}
+	e.buf = enc.AppendKey(e.buf, key)
+	e.buf = enc.AppendFloats32(e.buf, val)
+	return e
+}
+
+// Floats64 add Float64 array field to current entry
+func (e *Entry) Floats64(key string, val []float64) *Entry {
+	if e == nil {
+		return e
+	}
+	e.buf = enc.AppendKey(e.buf, key)
+	e.buf = enc.AppendFloats64(e.buf, val)
+	return e
+}
+
+// Bytes add bytes field as a string to current entry
+func (e *Entry) Bytes(key string, val []byte) *Entry {
+	if e == nil {
+		return e
+	}
+	e.buf = enc.AppendKey(e.buf, key)
+	e.buf = enc.AppendBytes(e.buf, val)
+	return e
+}
+
+// Hex add bytes field as a hex string to current entry
+func (e *Entry) Hex(key string, val []byte) *Entry {
+	if e == nil {
+		return e
+	}
+	e.buf = enc.AppendKey(e.buf, key)
+	e.buf = enc.AppendHex(e.buf, val)
+	return e
+}
+
+// Durs add Duration array field in milliseconds to current entry
+func (e *Entry) Durs(key string, val []time.Duration) *Entry {
+	if e == nil {
+		return e
+	}
+	e.buf = enc.AppendKey(e.buf, key)
+	e.buf = enc.AppendDurations(e.buf, val, time.Millisecond, false)
+	return e
+}
+
+// IPAddr add IPv4 or IPv6 address field to current entry
+func (e *Entry) IPAddr(key string, val net.IP) *Entry {
+	if e == nil {
+		return e
+	}
+	e.buf = enc.AppendKey(e.buf, key)
+	e.buf = enc.AppendIPAddr(e.buf, val)
+	return e
+}
+
+// IPPrefix add IPv4 or IPv6 prefix (address and mask) field to current entry
+func (e *Entry) IPPrefix(key string, val net.IPNet) *Entry {
+	if e == nil {
+		return e
+	}
+	e.buf = enc.AppendKey(e.buf, key)
+	e.buf = enc.AppendIPPrefix(e.buf, val)
+	return e
+}
+
+// MACAddr add MAC address field to current entry
+func (e *Entry) MACAddr(key string, val net.HardwareAddr) *Entry {
+	if e == nil {
+		return e
+	}
+	e.buf = enc.AppendKey(e.buf, key)
+	e.buf = enc.AppendMACAddr(e.buf, val)
+	return e
+}
+
+// RawJSON add already encoded JSON field to current entry.  The value must be valid JSON
+func (e *Entry) RawJSON(key string, val []byte) *Entry {
+	if e == nil {
+		return e
+	}
+	e.buf = enc.AppendKey(e.buf, key)
+	e.buf = append(e.buf, val...)
+	return e
+}
+
 // StackTrace adds stack_trace field to the current context
 func (e *Entry) StackTrace() *Entry {
 	if e == nil {
 		return e
 	}
-	e.buf = enc.AppendKey(e.buf, "stack_trace")
-	e.buf = enc.AppendString(e.buf, getStackTrace())
+	e.buf = enc.AppendKey(e.buf, e.logger.FieldNames().Stack)
+	e.buf = e.logger.appendStackTrace(e.buf, e.errStack)
 	return e
 }
 
+// JSON returns a copy of the entry as the JSON object which is passed to Write.  Handlers can
+// use it in BeforeWriting to read the fields of the entry.
+func (e *Entry) JSON() []byte {
+	return e.appendEnd(append([]byte(nil), e.buf...), e.FieldNames())
+}
+
+// appendEnd appends the message field and the end of the JSON object to buf
+func (e *Entry) appendEnd(buf []byte, names FieldNames) []byte {
+	if len(e.Message) > 0 {
+		buf = enc.AppendKey(buf, names.Message)
+		buf = enc.AppendString(buf, e.Message)
+	}
+
+	buf = enc.AppendEndMarker(buf)
+	return enc.AppendLineBreak(buf)
+}
+
 func handler(e *Entry) {
+	if !e.logger.enabledFor(e.name, e.Level) || !e.logger.sample(e) {
+		putEntry(e)
+		return
+	}
+
+	names := e.logger.FieldNames()
+
+	timestamp := e.logger.timestampConfig()
+	if timestamp.Enabled {
+		e.buf = enc.AppendKey(e.buf, names.Time)
+		e.buf = enc.AppendTime(e.buf, e.logger.now().In(timestamp.Location), timestamp.Format)
+	}
+
+	if len(e.name) > 0 {
+		e.buf = enc.AppendKey(e.buf, names.Logger)
+		e.buf = enc.AppendString(e.buf, e.name)
+	}
 
-	for _, h := range e.logger.cacheLeveledHandlers(e.Level) {
+	e.appendSpan(names)
+
+	caller := e.logger.callerConfig()
+	if caller.Enabled {
+		// frames: appendCaller, handler, entry's method and the caller
+		e.appendCaller(caller, names, 3+e.callerSkip+caller.Skip)
+	}
+
+	set := e.logger.acquireHandlerSet()
+	defer set.release()
+
+	// the hooks of the logger run once, so they can drop the entry or change its level
+	// before the handlers are chosen
+	if !runHooks(e, set.hooks) {
+		putEntry(e)
+		return
+	}
+
+	for _, h := range set.cacheLeveledHandlers(e.Level) {
 
 		newEntry := copyEntry(e)
 
-		// call hook interface
-		for _, hooker := range _logger.hooks {
-			_ = hooker(newEntry)
+		if !runHooks(newEntry, set.handlerHooks[h]) {
+			putEntry(newEntry)
+			continue
 		}
 
 		err := h.BeforeWriting(newEntry)
-		if err != nil {
-			stdlog.Printf("log: log hook failed: %v", err)
+		if errors.Is(err, ErrDropEntry) {
+			putEntry(newEntry)
+			continue
 		}
-
-		if len(newEntry.Message) > 0 {
-			newEntry.buf = enc.AppendKey(newEntry.buf, "msg")
-			newEntry.buf = enc.AppendString(newEntry.buf, newEntry.Message)
+		if err != nil {
+			ReportError("log: log hook failed: %v", err)
 		}
 
-		newEntry.buf = enc.AppendEndMarker(newEntry.buf)
-		newEntry.buf = enc.AppendLineBreak(newEntry.buf)
+		newEntry.buf = newEntry.appendEnd(newEntry.buf, names)
 
-		err = h.Write(newEntry.buf)
+		err = WriteFieldNames(h, newEntry.buf, names)
 		if err != nil {
-			if ErrorHandler != nil {
-				ErrorHandler(err)
-			} else {
-				stdlog.Printf("log: log write failed: %v", err)
-			}
+			ReportError("log: log write failed: %v", err)
 		}
 
 		putEntry(newEntry)
@@ -492,3 +760,18 @@ func handler(e *Entry) {
 
 	putEntry(e)
 }
+
+// runHooks calls the hooks in order.  It returns false if a hook drops the entry.
+func runHooks(e *Entry, hooks []Hookfunc) bool {
+	for _, hook := range hooks {
+		err := hook(e)
+		if err == nil {
+			continue
+		}
+		if errors.Is(err, ErrDropEntry) {
+			return false
+		}
+		ReportError("log: log hook failed: %v", err)
+	}
+	return true
+}
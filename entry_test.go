@@ -1,6 +1,9 @@
 package log
 
 import (
+	"errors"
+	"net"
+	"strings"
 	"testing"
 	"time"
 
@@ -47,3 +50,117 @@ func TestEntryFields(t *testing.T) {
 	assert.Equal(t, `{"hello":"world","strs":["str1","str2"],"is_enabled":true,"int":1,"ints":[1,2],"int8":2,"int16":3,"int32":4,"int64":5,"uint":6,"uint8":7,"uint16":8,"uint32":9,"uint64":10,"float32":11.123,"float64":12.123,"time":"2012-11-01T22:08:41Z","times":["2012-11-01T22:08:41Z","2012-11-01T22:08:41+08:00"],"person":{"Name":"","Age":0}`, string(entry.buf))
 
 }
+
+func TestEntryTypedFields(t *testing.T) {
+	entry := newEntry(_logger, nil)
+
+	_, ipNet, _ := net.ParseCIDR("192.168.0.0/24")
+	mac, _ := net.ParseMAC("00:1a:2b:3c:4d:5e")
+
+	entry = entry.
+		Bools("bools", []bool{true, false}).
+		Ints8("ints8", []int8{1, 2}).
+		Ints16("ints16", []int16{3}).
+		Ints32("ints32", []int32{4}).
+		Ints64("ints64", []int64{5}).
+		Uints("uints", []uint{6}).
+		Uints8("uints8", []uint8{7}).
+		Uints16("uints16", []uint16{8}).
+		Uints32("uints32", []uint32{9}).
+		Uints64("uints64", []uint64{10}).
+		Floats32("floats32", []float32{1.5}).
+		Floats64("floats64", []float64{2.5}).
+		Bytes("bytes", []byte("hello")).
+		Hex("hex", []byte{0xde, 0xad}).
+		Dur("dur", time.Second).
+		Durs("durs", []time.Duration{time.Second, 2 * time.Millisecond}).
+		IPAddr("ip", net.ParseIP("10.0.0.1")).
+		IPPrefix("prefix", *ipNet).
+		MACAddr("mac", mac).
+		RawJSON("raw", []byte(`{"a":1}`)).
+		Stringer("stringer", time.Minute).
+		Stringer("nil_stringer", nil)
+
+	assert.Equal(t, `{"bools":[true,false],"ints8":[1,2],"ints16":[3],"ints32":[4],"ints64":[5],"uints":[6],"uints8":[7],"uints16":[8],"uints32":[9],"uints64":[10],"floats32":[1.5],"floats64":[2.5],"bytes":"hello","hex":"dead","dur":1000,"durs":[1000,2],"ip":"10.0.0.1","prefix":"192.168.0.0/24","mac":"00:1a:2b:3c:4d:5e","raw":{"a":1},"stringer":"1m0s","nil_stringer":null`, string(entry.buf))
+}
+
+func TestEntryErr(t *testing.T) {
+	entry := newEntry(_logger, nil)
+	entry = entry.Err(errors.New("oops")).Errs("errs", []error{errors.New("a"), errors.New("b")})
+	assert.Equal(t, `{"error":"oops","errs":["a","b"]`, string(entry.buf))
+	putEntry(entry)
+}
+
+func TestCopyEntry(t *testing.T) {
+	entry := newEntry(_logger, nil)
+	entry.Str("long", strings.Repeat("a", 600))
+	want := string(entry.buf)
+
+	copied := copyEntry(entry)
+	copied.Str("more", "b")
+	copied.buf[2] = 'x'
+	assert.Equal(t, want, string(entry.buf))
+	assert.Equal(t, len(want)+len(`,"more":"b"`), len(copied.buf))
+	putEntry(copied)
+	putEntry(entry)
+}
+
+func TestEntryDict(t *testing.T) {
+	entry := newEntry(_logger, nil)
+	entry = entry.Str("a", "b").Dict("dict", func(d *Dict) {
+		d.Int8("int8", 1).Uint("uint", 2).Dict("nested", func(d *Dict) {
+			d.Interface("person", Person{Name: "john"})
+		})
+	})
+	assert.Equal(t, `{"a":"b","dict":{"int8":1,"uint":2,"nested":{"person":{"Name":"john","Age":0}}}`, string(entry.buf))
+	putEntry(entry)
+}
+
+func BenchmarkEntryDict(b *testing.B) {
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		entry := newEntry(_logger, nil)
+		entry.Dict("user", func(d *Dict) {
+			d.Str("name", "john").Int("age", 18)
+		})
+		putEntry(entry)
+	}
+}
+
+type point struct {
+	X, Y int
+}
+
+func (p *point) MarshalLogObject(d *Dict) {
+	d.Int("x", p.X).Int("y", p.Y)
+}
+
+type points []point
+
+func (p points) MarshalLogArray(a *Array) {
+	for i := range p {
+		a.Object(&p[i])
+	}
+}
+
+func TestEntryObjectAllocs(t *testing.T) {
+	if raceEnabled {
+		t.Skip("sync.Pool drops items with the race detector")
+	}
+
+	pts := points{{1, 2}, {3, 4}}
+	var obj LogObjectMarshaler = &pts[0]
+	var arr LogArrayMarshaler = pts
+
+	allocs := testing.AllocsPerRun(100, func() {
+		entry := newEntry(_logger, nil)
+		entry.Object("point", obj).Array("points", arr)
+		putEntry(entry)
+	})
+	assert.Equal(t, float64(0), allocs)
+
+	entry := newEntry(_logger, nil)
+	entry.Object("point", obj).Array("points", arr)
+	assert.Equal(t, `{"point":{"x":1,"y":2},"points":[{"x":1,"y":2},{"x":3,"y":4}]`, string(entry.buf))
+	putEntry(entry)
+}
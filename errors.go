@@ -0,0 +1,145 @@
+package log
+
+import (
+	"errors"
+	"fmt"
+)
+
+// ErrorConfig configures how Err and Errs encode the errors
+type ErrorConfig struct {
+	// Structured writes an error as an object with the message, the Go type and the chain of
+	// the wrapped errors, e.g.
+	//
+	//	{"message":"query: not found","type":"*fmt.wrapError","chain":[{"message":"not found","type":"*errors.errorString"}]}
+	//
+	// An error which implements LogObjectMarshaler adds its own fields into the object.
+	// Default: the error is written as the string of "%+v"
+	Structured bool
+}
+
+// SetErrors configures how Err and Errs encode the errors
+func (l *Logger) SetErrors(config ErrorConfig) {
+	l.errors.Store(config)
+}
+
+// SetErrors configures how Err and Errs of the default logger encode the errors
+func SetErrors(config ErrorConfig) {
+	_logger.SetErrors(config)
+}
+
+// appendError appends err to buf.  A nil error is written as null when the errors are structured.
+func (l *Logger) appendError(buf []byte, err error) []byte {
+	if !l.errors.Load().(ErrorConfig).Structured {
+		return enc.AppendString(buf, fmt.Sprintf("%+v", err))
+	}
+	if err == nil {
+		return enc.AppendNil(buf)
+	}
+
+	buf = enc.AppendBeginMarker(buf)
+	buf = appendErrorFields(buf, err)
+	if chain := errorChain(nil, err); len(chain) > 0 {
+		buf = enc.AppendKey(buf, "chain")
+		buf = enc.AppendArrayStart(buf)
+		for i, e := range chain {
+			if i > 0 {
+				buf = enc.AppendArrayDelim(buf)
+			}
+			buf = enc.AppendBeginMarker(buf)
+			buf = appendErrorFields(buf, e)
+			buf = enc.AppendEndMarker(buf)
+		}
+		buf = enc.AppendArrayEnd(buf)
+	}
+	return enc.AppendEndMarker(buf)
+}
+
+// appendErrorFields appends the message, the type and the fields of LogObjectMarshaler of err
+func appendErrorFields(buf []byte, err error) []byte {
+	buf = enc.AppendKey(buf, "message")
+	buf = enc.AppendString(buf, err.Error())
+	buf = enc.AppendKey(buf, "type")
+	buf = enc.AppendString(buf, fmt.Sprintf("%T", err))
+
+	if obj, ok := err.(LogObjectMarshaler); ok {
+		d := dictPool.Get().(*Dict)
+		d.buf = buf
+		obj.MarshalLogObject(d)
+		buf = d.buf
+		d.buf = nil
+		dictPool.Put(d)
+	}
+	return buf
+}
+
+// errorChain appends the errors wrapped by err in depth-first order.  Both errors.Unwrap and
+// the Unwrap() []error method of errors.Join are followed.
+func errorChain(chain []error, err error) []error {
+	switch x := err.(type) {
+	case interface{ Unwrap() []error }:
+		for _, e := range x.Unwrap() {
+			if e != nil {
+				chain = errorChain(append(chain, e), e)
+			}
+		}
+	default:
+		if e := errors.Unwrap(err); e != nil {
+			chain = errorChain(append(chain, e), e)
+		}
+	}
+	return chain
+}
+
+// appendErrors appends errs to buf as an array
+func (l *Logger) appendErrors(buf []byte, errs []error) []byte {
+	buf = enc.AppendArrayStart(buf)
+	for i, err := range errs {
+		if i > 0 {
+			buf = enc.AppendArrayDelim(buf)
+		}
+		buf = l.appendError(buf, err)
+	}
+	return enc.AppendArrayEnd(buf)
+}
+
+// Err add error field to current entry
+func (e *Entry) Err(err error) *Entry {
+	if e == nil {
+		return e
+	}
+	e.buf = enc.AppendKey(e.buf, e.logger.FieldNames().Error)
+	e.buf = e.logger.appendError(e.buf, err)
+	if stack := errorStack(err); stack != nil {
+		e.errStack = stack
+	}
+	return e
+}
+
+// Errs add error array field to current entry
+func (e *Entry) Errs(key string, errs []error) *Entry {
+	if e == nil {
+		return e
+	}
+	e.buf = enc.AppendKey(e.buf, key)
+	e.buf = e.logger.appendErrors(e.buf, errs)
+	return e
+}
+
+// Errs add error array field to current context
+func (c Context) Errs(key string, errs []error) Context {
+	c.buf = copyBytes(c.buf)
+	c.buf = enc.AppendKey(c.buf, key)
+	c.buf = c.getLogger().appendErrors(c.buf, errs)
+	return c
+}
+
+// Errs add error array field to current context
+func (l *Logger) Errs(key string, errs []error) Context {
+	c := newContext(l)
+	return c.Errs(key, errs)
+}
+
+// Errs add error array field to current context
+func Errs(key string, errs []error) Context {
+	return _logger.Errs(key, errs)
+}
@@ -0,0 +1,70 @@
+package log_test
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/jasonsoft/log/v2"
+	"github.com/jasonsoft/log/v2/handlers/memory"
+	"github.com/stretchr/testify/assert"
+)
+
+type codeError struct {
+	code int
+}
+
+func (e *codeError) Error() string {
+	return fmt.Sprintf("code %d", e.code)
+}
+
+func (e *codeError) MarshalLogObject(d *log.Dict) {
+	d.Int("code", e.code)
+}
+
+// joinError wraps several errors as errors.Join of Go 1.20 does
+type joinError []error
+
+func (e joinError) Error() string {
+	msgs := make([]string, len(e))
+	for i, err := range e {
+		msgs[i] = err.Error()
+	}
+	return strings.Join(msgs, "\n")
+}
+
+func (e joinError) Unwrap() []error {
+	return e
+}
+
+func TestErrDefault(t *testing.T) {
+	logger := log.New()
+	h := memory.New()
+	logger.AddHandler(h, log.AllLevels...)
+
+	err := fmt.Errorf("query: %w", errors.New("not found"))
+	logger.Err(err).Errs("errs", []error{err, nil}).Info("oops")
+	assert.Equal(t, `{"error":"query: not found","errs":["query: not found","<nil>"],"level":"INFO","msg":"oops"}`+"\n", string(h.Out))
+}
+
+func TestErrStructured(t *testing.T) {
+	logger := log.New()
+	h := memory.New()
+	logger.AddHandler(h, log.AllLevels...)
+	logger.SetErrors(log.ErrorConfig{Structured: true})
+
+	err := fmt.Errorf("query: %w", &codeError{code: 404})
+	logger.Err(err).Info("oops")
+	assert.Equal(t, `{"error":{"message":"query: code 404","type":"*fmt.wrapError","chain":[{"message":"code 404","type":"*log_test.codeError","code":404}]},"level":"INFO","msg":"oops"}`+"\n", string(h.Out))
+
+	joined := joinError{errors.New("a"), fmt.Errorf("b: %w", errors.New("c"))}
+	logger.Err(joined).Info("joined")
+	assert.Equal(t, `{"error":{"message":"a\nb: c","type":"log_test.joinError","chain":[{"message":"a","type":"*errors.errorString"},{"message":"b: c","type":"*fmt.wrapError"},{"message":"c","type":"*errors.errorString"}]},"level":"INFO","msg":"joined"}`+"\n", string(h.Out))
+
+	logger.Errs("errs", []error{&codeError{code: 1}, nil}).Info("errs")
+	assert.Equal(t, `{"errs":[{"message":"code 1","type":"*log_test.codeError","code":1},null],"level":"INFO","msg":"errs"}`+"\n", string(h.Out))
+
+	logger.Err(nil).Info("nil")
+	assert.Equal(t, `{"error":null,"level":"INFO","msg":"nil"}`+"\n", string(h.Out))
+}
@@ -1,58 +1,79 @@
 package main
 
-// import (
-// 	"errors"
-// 	"fmt"
-
-// 	"github.com/jasonsoft/log"
-// 	"github.com/jasonsoft/log/handlers/console"
-// 	pkgErr "github.com/pkg/errors"
-// )
-
-// var ErrNotFound = pkgErr.New("record not found")
-
-// func main() {
-// 	clog := console.New()
-// 	log.RegisterHandler(clog, log.AllLevels...) // use console handler to log all level log
-
-// 	err := http()
-// 	if err != nil {
-// 		if errors.Is(err, ErrNotFound) {
-// 			fmt.Printf("err is ErrNotFound: %v\n", err)
-// 		}
-
-// 		var errDup error
-// 		if errors.As(err, &errDup) {
-// 			fmt.Printf("err as : %v\n", errDup)
-// 		}
-
-// 		if pkgErr.Cause(err) == ErrNotFound {
-// 			fmt.Printf("root ErrNotFound error: %v\n", err)
-// 		}
-
-// 		// format the error to a string and print it
-// 		//formattedStr := eris.ToString(err, true)
-
-// 		log.WithError(err).Error("oops")
-// 	}
-// }
-
-// func repo() error {
-// 	return pkgErr.Wrap(ErrNotFound, "id 6 was not found")
-// }
-
-// func service() error {
-// 	err := repo()
-// 	if err != nil {
-// 		return err
-// 	}
-// 	return nil
-// }
-
-// func http() error {
-// 	err := service()
-// 	if err != nil {
-// 		return err
-// 	}
-// 	return nil
-// }
+import (
+	"errors"
+	"fmt"
+	"runtime"
+
+	"github.com/jasonsoft/log/v2"
+	"github.com/jasonsoft/log/v2/handlers/console"
+)
+
+var ErrNotFound = errors.New("record not found")
+
+// stackError records the stack where it is created like github.com/pkg/errors, and
+// it implements log.StackFramer, so the stack trace of the entry points to repo
+// instead of the logging call in main.
+type stackError struct {
+	msg   string
+	err   error
+	stack []uintptr
+}
+
+func wrap(err error, msg string) error {
+	pcs := make([]uintptr, 32)
+	n := runtime.Callers(2, pcs)
+	return &stackError{msg: msg, err: err, stack: pcs[:n]}
+}
+
+func (e *stackError) Error() string {
+	return e.msg + ": " + e.err.Error()
+}
+
+func (e *stackError) Unwrap() error {
+	return e.err
+}
+
+func (e *stackError) Frames() []uintptr {
+	return e.stack
+}
+
+func main() {
+	clog := console.New()
+	log.AddHandler(clog, log.AllLevels...) // use console handler to log all level log
+	defer log.Flush()
+
+	// write the error as an object with its type and the wrapped errors, and
+	// the stack trace as frames
+	log.SetErrors(log.ErrorConfig{Structured: true})
+	log.SetStackTrace(log.StackConfig{Frames: true, Depth: 5})
+
+	err := http()
+	if err != nil {
+		if errors.Is(err, ErrNotFound) {
+			fmt.Printf("err is ErrNotFound: %v\n", err)
+		}
+
+		log.Err(err).Error("oops")
+	}
+}
+
+func repo() error {
+	return wrap(ErrNotFound, "id 6 was not found")
+}
+
+func service() error {
+	err := repo()
+	if err != nil {
+		return fmt.Errorf("service: %w", err)
+	}
+	return nil
+}
+
+func http() error {
+	err := service()
+	if err != nil {
+		return err
+	}
+	return nil
+}
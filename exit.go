@@ -0,0 +1,75 @@
+package log
+
+import (
+	"os"
+)
+
+// PanicError is the value which PanicWithError panics with.  It carries the message
+// and the fields of the entry.
+type PanicError struct {
+	Message string
+	// Fields is the JSON object of the entry's fields, e.g. {"user_id":1}
+	Fields []byte
+}
+
+// Error implements error.
+func (e *PanicError) Error() string {
+	return e.Message
+}
+
+// PanicFunc is called after a panic level entry is written
+type PanicFunc func(err *PanicError)
+
+// PanicWithMessage panics with the message of the entry.  It is the default PanicFunc.
+func PanicWithMessage(err *PanicError) {
+	panic(err.Message)
+}
+
+// PanicWithError panics with the *PanicError, so the recovered value carries the entry's fields
+func PanicWithError(err *PanicError) {
+	panic(err)
+}
+
+// SetExitFunc sets the func which Fatal uses to exit the process after the exit hooks are called
+// and the handlers are flushed.  A nil func resets to os.Exit.  If the func returns, Fatal returns
+// too, and the handlers are still registered, so the logger keeps writing.
+func (l *Logger) SetExitFunc(fn func(code int)) {
+	if fn == nil {
+		fn = os.Exit
+	}
+	l.exitFunc.Store(fn)
+}
+
+// SetPanicFunc sets the func which Panic calls after the entry is written.  A nil func resets to
+// PanicWithMessage.  If the func returns, Panic returns too.
+func (l *Logger) SetPanicFunc(fn PanicFunc) {
+	if fn == nil {
+		fn = PanicWithMessage
+	}
+	l.panicFunc.Store(fn)
+}
+
+// SetExitFunc sets the func which Fatal of the default logger uses to exit the process
+func SetExitFunc(fn func(code int)) {
+	_logger.SetExitFunc(fn)
+}
+
+// SetPanicFunc sets the func which Panic of the default logger calls after the entry is written
+func SetPanicFunc(fn PanicFunc) {
+	_logger.SetPanicFunc(fn)
+}
+
+// panicError captures the message and fields of the entry before it is written
+func (e *Entry) panicError() *PanicError {
+	fields := make([]byte, 0, len(e.buf)+1)
+	fields = append(fields, e.buf...)
+	fields = enc.AppendEndMarker(fields)
+	return &PanicError{
+		Message: e.Message,
+		Fields:  fields,
+	}
+}
+
+func (l *Logger) panic(err *PanicError) {
+	l.panicFunc.Load().(PanicFunc)(err)
+}
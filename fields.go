@@ -0,0 +1,91 @@
+package log
+
+// FieldNames are the names of the built-in fields
+type FieldNames struct {
+	// Message is the name of the message field. Default: msg
+	Message string
+	// Level is the name of the level field which is added by handlers. Default: level
+	Level string
+	// Time is the name of the timestamp field. Default: time
+	Time string
+	// Error is the name of the error field. Default: error
+	Error string
+	// Stack is the name of the stack trace field. Default: stack_trace
+	Stack string
+	// Caller is the name of the caller field. Default: caller
+	Caller string
+	// Func is the name of the calling function field. Default: func
+	Func string
+	// Duration is the name of the duration field of Trace. Default: duration
+	Duration string
+	// Logger is the name of the field for named contexts. Default: logger
+	Logger string
+	// TraceID is the name of the trace ID field. Default: trace_id
+	TraceID string
+	// SpanID is the name of the span ID field. Default: span_id
+	SpanID string
+	// ParentSpanID is the name of the parent span ID field of child spans. Default: parent_span_id
+	ParentSpanID string
+	// TraceFlags is the name of the trace flags field. Default: trace_flags
+	TraceFlags string
+}
+
+// DefaultFieldNames returns the default names of the built-in fields
+func DefaultFieldNames() FieldNames {
+	return FieldNames{
+		Message:      "msg",
+		Level:        "level",
+		Time:         "time",
+		Error:        "error",
+		Stack:        "stack_trace",
+		Caller:       "caller",
+		Func:         "func",
+		Duration:     "duration",
+		Logger:       "logger",
+		TraceID:      "trace_id",
+		SpanID:       "span_id",
+		ParentSpanID: "parent_span_id",
+		TraceFlags:   "trace_flags",
+	}
+}
+
+// SetFieldNames changes the names of the built-in fields.  Empty names use the default names.
+func (l *Logger) SetFieldNames(names FieldNames) {
+	defaults := DefaultFieldNames()
+	setDefault(&names.Message, defaults.Message)
+	setDefault(&names.Level, defaults.Level)
+	setDefault(&names.Time, defaults.Time)
+	setDefault(&names.Error, defaults.Error)
+	setDefault(&names.Stack, defaults.Stack)
+	setDefault(&names.Caller, defaults.Caller)
+	setDefault(&names.Func, defaults.Func)
+	setDefault(&names.Duration, defaults.Duration)
+	setDefault(&names.Logger, defaults.Logger)
+	setDefault(&names.TraceID, defaults.TraceID)
+	setDefault(&names.SpanID, defaults.SpanID)
+	setDefault(&names.ParentSpanID, defaults.ParentSpanID)
+	setDefault(&names.TraceFlags, defaults.TraceFlags)
+	l.fieldNames.Store(names)
+}
+
+// FieldNames returns the names of the built-in fields
+func (l *Logger) FieldNames() FieldNames {
+	return l.fieldNames.Load().(FieldNames)
+}
+
+// FieldNames returns the names of the built-in fields of the entry's logger.  Handlers
+// should use it to name the fields they add, e.g. the level field.
+func (e *Entry) FieldNames() FieldNames {
+	return e.logger.FieldNames()
+}
+
+// SetFieldNames changes the names of the built-in fields of the default logger
+func SetFieldNames(names FieldNames) {
+	_logger.SetFieldNames(names)
+}
+
+func setDefault(name *string, defaultName string) {
+	if len(*name) == 0 {
+		*name = defaultName
+	}
+}
@@ -0,0 +1,44 @@
+package log_test
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/jasonsoft/log/v2"
+	"github.com/jasonsoft/log/v2/handlers/memory"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestFieldNames(t *testing.T) {
+	logger := log.New()
+	h := memory.New()
+	logger.AddHandler(h, log.AllLevels...)
+	logger.SetClock(fixedClock{t: time.Date(2020, 8, 26, 0, 0, 0, 0, time.UTC)})
+	logger.SetTimestamp(log.TimestampConfig{Enabled: true, Format: log.TimeFormatUnix})
+
+	names := log.FieldNames{
+		Message:  "message",
+		Level:    "severity",
+		Time:     "timestamp",
+		Error:    "err.message",
+		Logger:   "logger.name",
+		Duration: "elapsed",
+	}
+	logger.SetFieldNames(names)
+
+	expected := log.DefaultFieldNames()
+	expected.Message = "message"
+	expected.Level = "severity"
+	expected.Time = "timestamp"
+	expected.Error = "err.message"
+	expected.Logger = "logger.name"
+	expected.Duration = "elapsed"
+	assert.Equal(t, expected, logger.FieldNames())
+
+	logger.Named("db").Err(errors.New("oops")).Warn("too bad")
+	assert.Equal(t, `{"err.message":"oops","timestamp":1598400000,"logger.name":"db","severity":"WARN","message":"too bad"}`+"\n", string(h.Out))
+
+	logger.Trace("trace").Stop()
+	assert.Equal(t, `{"elapsed":0,"timestamp":1598400000,"severity":"INFO","message":"trace"}`+"\n", string(h.Out))
+}
@@ -0,0 +1,260 @@
+// Package async implements a handler wrapper which writes entries to the underlying
+// handler in background goroutines, so a slow output doesn't block the callers.
+package async
+
+import (
+	"errors"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/jasonsoft/log/v2"
+)
+
+// Policy decides what to do when the queue is full
+type Policy int
+
+const (
+	// Block waits until the queue has space
+	Block Policy = iota
+	// DropNewest drops the entry which is being written
+	DropNewest
+	// DropOldest drops the oldest entry in the queue to make room for the new one
+	DropOldest
+)
+
+// ErrFlushTimeout is returned when the queue isn't drained within the flush timeout
+var ErrFlushTimeout = errors.New("async: flush timeout")
+
+// ErrClosed is returned when entries are written after the handler is closed
+var ErrClosed = errors.New("async: handler is closed")
+
+// Config is the configuration of the async handler
+type Config struct {
+	// QueueSize is the max number of entries in the queue. Default: 1024
+	QueueSize int
+	// Workers is the number of goroutines which write entries to the handler.
+	// Entries may be written out of order if there are more than one worker. Default: 1
+	Workers int
+	// Policy decides what to do when the queue is full. Default: Block
+	Policy Policy
+	// FlushTimeout is how long Flush waits for the queue to be drained. Default: 5 seconds
+	FlushTimeout time.Duration
+}
+
+// item is a queued entry with the field names of its logger
+type item struct {
+	buf   []byte
+	names log.FieldNames
+}
+
+// Handler implementation.
+type Handler struct {
+	handler      log.Handler
+	policy       Policy
+	flushTimeout time.Duration
+
+	mu       sync.Mutex
+	notEmpty *sync.Cond
+	notFull  *sync.Cond
+	queue    []item
+	free     [][]byte // buffers which can be reused
+	head     int
+	count    int
+	pending  int           // entries in the queue or being written
+	idle     chan struct{} // closed when pending becomes zero
+	closed   bool
+
+	dropped uint64
+}
+
+// New creates a new async handler which wraps the handler
+func New(handler log.Handler, config Config) *Handler {
+	if config.QueueSize <= 0 {
+		config.QueueSize = 1024
+	}
+	if config.Workers <= 0 {
+		config.Workers = 1
+	}
+	if config.FlushTimeout <= 0 {
+		config.FlushTimeout = 5 * time.Second
+	}
+
+	h := &Handler{
+		handler:      handler,
+		policy:       config.Policy,
+		flushTimeout: config.FlushTimeout,
+		queue:        make([]item, config.QueueSize),
+		idle:         make(chan struct{}),
+	}
+	h.notEmpty = sync.NewCond(&h.mu)
+	h.notFull = sync.NewCond(&h.mu)
+	close(h.idle)
+
+	for i := 0; i < config.Workers; i++ {
+		go h.work()
+	}
+	return h
+}
+
+// BeforeWriting implements log.Handler.  It is called synchronously because the entry
+// can't be used after the log call returns.
+func (h *Handler) BeforeWriting(e *log.Entry) error {
+	return h.handler.BeforeWriting(e)
+}
+
+// Write implements log.Handler for the entries which have the default field names.
+func (h *Handler) Write(bytes []byte) error {
+	return h.WriteFieldNames(bytes, log.DefaultFieldNames())
+}
+
+// WriteFieldNames implements log.FieldNamesWriter.  The bytes are copied into the queue and
+// written to the underlying handler later with the field names.
+func (h *Handler) WriteFieldNames(bytes []byte, names log.FieldNames) error {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	if h.closed {
+		return ErrClosed
+	}
+
+	for h.count == len(h.queue) {
+		if h.closed {
+			return ErrClosed
+		}
+		switch h.policy {
+		case DropNewest:
+			atomic.AddUint64(&h.dropped, 1)
+			return nil
+		case DropOldest:
+			h.putBuf(h.pop().buf)
+			h.done()
+			atomic.AddUint64(&h.dropped, 1)
+		default:
+			h.notFull.Wait()
+		}
+	}
+
+	var buf []byte
+	if n := len(h.free); n > 0 {
+		buf = h.free[n-1]
+		h.free = h.free[:n-1]
+	}
+	buf = append(buf[:0], bytes...)
+
+	h.queue[(h.head+h.count)%len(h.queue)] = item{buf: buf, names: names}
+	h.count++
+	if h.pending == 0 {
+		h.idle = make(chan struct{})
+	}
+	h.pending++
+	h.notEmpty.Signal()
+	return nil
+}
+
+// Flush waits until all queued entries have been written or the flush timeout expires,
+// then flushes the underlying handler if it implements log.Flusher.
+func (h *Handler) Flush() error {
+	h.mu.Lock()
+	idle := h.idle
+	h.mu.Unlock()
+
+	timer := time.NewTimer(h.flushTimeout)
+	defer timer.Stop()
+
+	select {
+	case <-idle:
+	case <-timer.C:
+		return ErrFlushTimeout
+	}
+
+	flusher, ok := h.handler.(log.Flusher)
+	if ok {
+		return flusher.Flush()
+	}
+	return nil
+}
+
+// Close flushes the queue and stops the workers, then closes the underlying handler
+// if it implements log.Closer.
+func (h *Handler) Close() error {
+	err := h.Flush()
+
+	h.mu.Lock()
+	h.closed = true
+	h.notEmpty.Broadcast()
+	h.notFull.Broadcast()
+	h.mu.Unlock()
+
+	closer, ok := h.handler.(log.Closer)
+	if ok {
+		closeErr := closer.Close()
+		if err == nil {
+			err = closeErr
+		}
+	}
+	return err
+}
+
+// Dropped returns the number of entries which were dropped because the queue was full
+func (h *Handler) Dropped() uint64 {
+	return atomic.LoadUint64(&h.dropped)
+}
+
+// Len returns the number of entries in the queue
+func (h *Handler) Len() int {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	return h.count
+}
+
+// pop removes the oldest entry from the queue.  The caller must hold the lock.
+func (h *Handler) pop() item {
+	it := h.queue[h.head]
+	h.queue[h.head] = item{}
+	h.head = (h.head + 1) % len(h.queue)
+	h.count--
+	return it
+}
+
+// putBuf keeps the buffer for reuse.  The caller must hold the lock.
+func (h *Handler) putBuf(buf []byte) {
+	if len(h.free) < len(h.queue) {
+		h.free = append(h.free, buf)
+	}
+}
+
+// done marks an entry as finished.  The caller must hold the lock.
+func (h *Handler) done() {
+	h.pending--
+	if h.pending == 0 {
+		close(h.idle)
+	}
+}
+
+func (h *Handler) work() {
+	for {
+		h.mu.Lock()
+		for h.count == 0 && !h.closed {
+			h.notEmpty.Wait()
+		}
+		if h.count == 0 {
+			// the handler is closed and the queue is drained
+			h.mu.Unlock()
+			return
+		}
+		it := h.pop()
+		h.notFull.Signal()
+		h.mu.Unlock()
+
+		err := log.WriteFieldNames(h.handler, it.buf, it.names)
+		if err != nil {
+			log.ReportError("log: async write failed: %v", err)
+		}
+
+		h.mu.Lock()
+		h.putBuf(it.buf)
+		h.done()
+		h.mu.Unlock()
+	}
+}
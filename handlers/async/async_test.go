@@ -0,0 +1,166 @@
+package async
+
+import (
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/jasonsoft/log/v2"
+	"github.com/stretchr/testify/assert"
+)
+
+type blockingHandler struct {
+	mu      sync.Mutex
+	release chan struct{}
+	lines   []string
+	flushed bool
+}
+
+func newBlockingHandler() *blockingHandler {
+	return &blockingHandler{
+		release: make(chan struct{}),
+	}
+}
+
+func (h *blockingHandler) BeforeWriting(e *log.Entry) error {
+	e.Str("level", e.Level.String())
+	return nil
+}
+
+func (h *blockingHandler) Write(bytes []byte) error {
+	<-h.release
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	h.lines = append(h.lines, strings.TrimSpace(string(bytes)))
+	return nil
+}
+
+func (h *blockingHandler) Flush() error {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	h.flushed = true
+	return nil
+}
+
+func (h *blockingHandler) Lines() []string {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	return h.lines
+}
+
+func TestAsyncWrite(t *testing.T) {
+	inner := newBlockingHandler()
+	close(inner.release)
+	h := New(inner, Config{})
+
+	logger := log.New()
+	logger.AddHandler(h, log.AllLevels...)
+	logger.Info("hello")
+	logger.Str("app", "santa").Warn("world")
+
+	err := h.Flush()
+	assert.NoError(t, err)
+	assert.True(t, inner.flushed)
+	assert.Equal(t, []string{
+		`{"level":"INFO","msg":"hello"}`,
+		`{"app":"santa","level":"WARN","msg":"world"}`,
+	}, inner.Lines())
+}
+
+func TestAsyncDropNewest(t *testing.T) {
+	inner := newBlockingHandler()
+	h := New(inner, Config{QueueSize: 2, Policy: DropNewest})
+
+	_ = h.Write([]byte("1"))
+	waitLen(t, h, 0) // the worker is blocked on writing "1"
+	_ = h.Write([]byte("2"))
+	_ = h.Write([]byte("3"))
+	_ = h.Write([]byte("4"))
+	assert.Equal(t, uint64(1), h.Dropped())
+
+	close(inner.release)
+	assert.NoError(t, h.Flush())
+	assert.Equal(t, []string{"1", "2", "3"}, inner.Lines())
+}
+
+func TestAsyncDropOldest(t *testing.T) {
+	inner := newBlockingHandler()
+	h := New(inner, Config{QueueSize: 2, Policy: DropOldest})
+
+	_ = h.Write([]byte("1"))
+	waitLen(t, h, 0)
+	_ = h.Write([]byte("2"))
+	_ = h.Write([]byte("3"))
+	_ = h.Write([]byte("4"))
+	assert.Equal(t, uint64(1), h.Dropped())
+
+	close(inner.release)
+	assert.NoError(t, h.Flush())
+	assert.Equal(t, []string{"1", "3", "4"}, inner.Lines())
+}
+
+func TestAsyncBlock(t *testing.T) {
+	inner := newBlockingHandler()
+	h := New(inner, Config{QueueSize: 1})
+
+	_ = h.Write([]byte("1"))
+	waitLen(t, h, 0)
+	_ = h.Write([]byte("2"))
+
+	written := make(chan struct{})
+	go func() {
+		_ = h.Write([]byte("3"))
+		close(written)
+	}()
+
+	select {
+	case <-written:
+		t.Fatal("write should block when the queue is full")
+	case <-time.After(50 * time.Millisecond):
+	}
+
+	close(inner.release)
+	<-written
+	assert.NoError(t, h.Flush())
+	assert.Equal(t, []string{"1", "2", "3"}, inner.Lines())
+	assert.Equal(t, uint64(0), h.Dropped())
+}
+
+func TestAsyncFlushTimeout(t *testing.T) {
+	inner := newBlockingHandler()
+	h := New(inner, Config{FlushTimeout: 10 * time.Millisecond})
+
+	_ = h.Write([]byte("1"))
+	assert.Equal(t, ErrFlushTimeout, h.Flush())
+	assert.False(t, inner.flushed)
+
+	close(inner.release)
+	assert.NoError(t, h.Flush())
+}
+
+func waitLen(t *testing.T, h *Handler, n int) {
+	deadline := time.Now().Add(time.Second)
+	for h.Len() != n {
+		if time.Now().After(deadline) {
+			t.Fatalf("queue length is %d, want %d", h.Len(), n)
+		}
+		time.Sleep(time.Millisecond)
+	}
+}
+
+func TestAsyncClose(t *testing.T) {
+	inner := newBlockingHandler()
+	close(inner.release)
+	h := New(inner, Config{Workers: 2})
+
+	logger := log.New()
+	logger.AddHandler(h, log.AllLevels...)
+	logger.Info("hello")
+
+	assert.NoError(t, h.Close())
+	assert.Equal(t, []string{`{"level":"INFO","msg":"hello"}`}, inner.Lines())
+	assert.True(t, inner.flushed)
+
+	assert.Equal(t, ErrClosed, h.Write([]byte("{}")))
+}
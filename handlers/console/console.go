@@ -53,27 +53,31 @@ func New() log.Handler {
 
 // BeforeWriting handles the log entry
 func (h *Console) BeforeWriting(e *log.Entry) error {
-	e.Str("level", e.Level.String())
-
+	e.Str(e.FieldNames().Level, e.Level.String())
 	return nil
 }
 
-// Write handles the log entry
+// Write handles the log entry which has the default field names
 func (h *Console) Write(bytes []byte) error {
+	return h.WriteFieldNames(bytes, log.DefaultFieldNames())
+}
+
+// WriteFieldNames implements log.FieldNamesWriter.
+func (h *Console) WriteFieldNames(bytes []byte, names log.FieldNames) error {
 	kv := map[string]interface{}{}
 	err := json.Unmarshal(bytes, &kv)
 	if err != nil {
 		return err
 	}
 
-	level := fmt.Sprintf("%v", kv["level"])
-	msg := kv["msg"]
+	level := fmt.Sprintf("%v", kv[names.Level])
+	msg := kv[names.Message]
 	color := levelToColor(level)
 
 	// sort map by key
 	keys := make([]string, 0, len(kv))
 	for k := range kv {
-		if k == "level" || k == "msg" {
+		if k == names.Level || k == names.Message {
 			continue
 		}
 		keys = append(keys, k)
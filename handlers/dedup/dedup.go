@@ -0,0 +1,193 @@
+// Package dedup implements a handler wrapper which suppresses duplicated entries within a
+// time window and writes a summary entry with the number of repeats when the window closes.
+package dedup
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/jasonsoft/log/v2"
+)
+
+// Config is the configuration of the dedup handler
+type Config struct {
+	// Window is how long duplicated entries are suppressed after the first one. Default: 10 seconds
+	Window time.Duration
+	// Keys are the fields which identify duplicated entries.  The level and message field names of the
+	// logger mean the level and message of the entry. Default: the level and message of the entry
+	Keys []string
+}
+
+type window struct {
+	last    []byte // the last suppressed entry
+	names   log.FieldNames
+	repeats int
+}
+
+// Handler implementation.
+type Handler struct {
+	handler log.Handler
+	window  time.Duration
+	keys    []string
+
+	mu      sync.Mutex
+	windows map[string]*window
+}
+
+// New creates a new dedup handler which wraps the handler
+func New(handler log.Handler, config Config) *Handler {
+	if config.Window <= 0 {
+		config.Window = 10 * time.Second
+	}
+	return &Handler{
+		handler: handler,
+		window:  config.Window,
+		keys:    config.Keys,
+		windows: map[string]*window{},
+	}
+}
+
+// BeforeWriting implements log.Handler.  The first entry of a window is written, and the
+// duplicated entries are dropped until the window closes.  The key is built before the wrapped
+// handler changes the entry, e.g. the gelf handler clears the message.
+func (h *Handler) BeforeWriting(e *log.Entry) error {
+	key := h.key(e)
+
+	err := h.handler.BeforeWriting(e)
+	if errors.Is(err, log.ErrDropEntry) {
+		return err
+	}
+
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	w, found := h.windows[key]
+	if found {
+		w.repeats++
+		w.last = e.JSON()
+		w.names = e.FieldNames()
+		return log.ErrDropEntry
+	}
+
+	w = &window{}
+	h.windows[key] = w
+	time.AfterFunc(h.window, func() {
+		h.close(key, w)
+	})
+	return err
+}
+
+// Write implements log.Handler.
+func (h *Handler) Write(bytes []byte) error {
+	return h.handler.Write(bytes)
+}
+
+// WriteFieldNames implements log.FieldNamesWriter.
+func (h *Handler) WriteFieldNames(bytes []byte, names log.FieldNames) error {
+	return log.WriteFieldNames(h.handler, bytes, names)
+}
+
+// Flush writes the summary of all open windows, then flushes the underlying
+// handler if it implements log.Flusher.
+func (h *Handler) Flush() error {
+	h.mu.Lock()
+	windows := h.windows
+	h.windows = map[string]*window{}
+	h.mu.Unlock()
+
+	for _, w := range windows {
+		h.writeSummary(w)
+	}
+
+	flusher, ok := h.handler.(log.Flusher)
+	if ok {
+		return flusher.Flush()
+	}
+	return nil
+}
+
+// Close flushes the handler, then closes the underlying handler if it implements log.Closer.
+func (h *Handler) Close() error {
+	err := h.Flush()
+
+	closer, ok := h.handler.(log.Closer)
+	if ok {
+		closeErr := closer.Close()
+		if err == nil {
+			err = closeErr
+		}
+	}
+	return err
+}
+
+func (h *Handler) close(key string, w *window) {
+	h.mu.Lock()
+	if h.windows[key] != w {
+		// the window has been closed by Flush
+		h.mu.Unlock()
+		return
+	}
+	delete(h.windows, key)
+	h.mu.Unlock()
+
+	h.writeSummary(w)
+}
+
+func (h *Handler) writeSummary(w *window) {
+	if w.repeats == 0 {
+		return
+	}
+
+	err := log.WriteFieldNames(h.handler, appendRepeatCount(w.last, w.repeats), w.names)
+	if err != nil {
+		log.ReportError("log: dedup write failed: %v", err)
+	}
+}
+
+// key returns the level and message of the entry, or the values of the key fields
+func (h *Handler) key(e *log.Entry) string {
+	var sb strings.Builder
+	if len(h.keys) == 0 {
+		_, _ = sb.WriteString(e.Level.String())
+		_ = sb.WriteByte(0)
+		_, _ = sb.WriteString(e.Message)
+		return sb.String()
+	}
+
+	names := e.FieldNames()
+	kv := map[string]json.RawMessage{}
+	_ = json.Unmarshal(e.JSON(), &kv)
+	for _, k := range h.keys {
+		switch k {
+		case names.Level:
+			_, _ = sb.WriteString(e.Level.String())
+		case names.Message:
+			_, _ = sb.WriteString(e.Message)
+		default:
+			_, _ = sb.Write(kv[k])
+		}
+		_ = sb.WriteByte(0)
+	}
+	return sb.String()
+}
+
+// appendRepeatCount adds repeat_count field into the end of the JSON object
+func appendRepeatCount(b []byte, repeats int) []byte {
+	b = bytes.TrimRight(b, " \r\n")
+	if len(b) == 0 || b[len(b)-1] != '}' {
+		return b
+	}
+
+	b = b[:len(b)-1]
+	if b[len(b)-1] != '{' {
+		b = append(b, ',')
+	}
+	b = append(b, `"repeat_count":`...)
+	b = strconv.AppendInt(b, int64(repeats), 10)
+	return append(b, '}', '\n')
+}
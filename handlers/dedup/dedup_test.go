@@ -0,0 +1,158 @@
+package dedup
+
+import (
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/jasonsoft/log/v2"
+	"github.com/stretchr/testify/assert"
+)
+
+type recordHandler struct {
+	mu    sync.Mutex
+	lines []string
+}
+
+func (h *recordHandler) BeforeWriting(e *log.Entry) error {
+	e.Str(e.FieldNames().Level, e.Level.String())
+	return nil
+}
+
+func (h *recordHandler) Write(bytes []byte) error {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	h.lines = append(h.lines, strings.TrimSpace(string(bytes)))
+	return nil
+}
+
+func (h *recordHandler) Lines() []string {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	return h.lines
+}
+
+func TestDedupFlush(t *testing.T) {
+	inner := &recordHandler{}
+	h := New(inner, Config{Window: time.Hour})
+
+	logger := log.New()
+	logger.AddHandler(h, log.AllLevels...)
+
+	for i := 0; i < 5; i++ {
+		logger.Int("i", i).Warn("connection refused")
+	}
+	logger.Info("connection refused")
+	assert.Equal(t, []string{
+		`{"i":0,"level":"WARN","msg":"connection refused"}`,
+		`{"level":"INFO","msg":"connection refused"}`,
+	}, inner.Lines())
+
+	assert.NoError(t, h.Flush())
+	assert.Equal(t, []string{
+		`{"i":0,"level":"WARN","msg":"connection refused"}`,
+		`{"level":"INFO","msg":"connection refused"}`,
+		`{"i":4,"level":"WARN","msg":"connection refused","repeat_count":4}`,
+	}, inner.Lines())
+
+	// a new window starts after flushing
+	logger.Warn("connection refused")
+	assert.Equal(t, `{"level":"WARN","msg":"connection refused"}`, inner.Lines()[3])
+}
+
+func TestDedupKeys(t *testing.T) {
+	inner := &recordHandler{}
+	h := New(inner, Config{Window: time.Hour, Keys: []string{"level", "msg", "host"}})
+
+	logger := log.New()
+	logger.AddHandler(h, log.AllLevels...)
+
+	logger.Str("host", "a").Int("i", 1).Warn("oops")
+	logger.Str("host", "b").Int("i", 2).Warn("oops")
+	logger.Str("host", "a").Int("i", 3).Warn("oops")
+	logger.Str("host", "a").Int("i", 4).Info("oops")
+
+	assert.Equal(t, []string{
+		`{"host":"a","i":1,"level":"WARN","msg":"oops"}`,
+		`{"host":"b","i":2,"level":"WARN","msg":"oops"}`,
+		`{"host":"a","i":4,"level":"INFO","msg":"oops"}`,
+	}, inner.Lines())
+}
+
+func TestDedupWindow(t *testing.T) {
+	inner := &recordHandler{}
+	h := New(inner, Config{Window: 20 * time.Millisecond})
+
+	logger := log.New()
+	logger.AddHandler(h, log.AllLevels...)
+
+	logger.Warn("oops")
+	logger.Warn("oops")
+	logger.Warn("oops")
+
+	deadline := time.Now().Add(time.Second)
+	for len(inner.Lines()) < 2 && time.Now().Before(deadline) {
+		time.Sleep(time.Millisecond)
+	}
+
+	assert.Equal(t, []string{
+		`{"level":"WARN","msg":"oops"}`,
+		`{"level":"WARN","msg":"oops","repeat_count":2}`,
+	}, inner.Lines())
+
+	// nothing is written when the window has no repeat
+	logger.Info("once")
+	time.Sleep(50 * time.Millisecond)
+	assert.Equal(t, 3, len(inner.Lines()))
+}
+
+func TestDedupFieldNames(t *testing.T) {
+	inner := &recordHandler{}
+	h := New(inner, Config{Window: time.Hour})
+
+	logger := log.New()
+	logger.SetFieldNames(log.FieldNames{Level: "severity", Message: "message"})
+	logger.AddHandler(h, log.AllLevels...)
+
+	logger.Warn("a")
+	logger.Warn("b")
+	logger.Warn("a")
+	assert.NoError(t, h.Flush())
+
+	assert.Equal(t, []string{
+		`{"severity":"WARN","message":"a"}`,
+		`{"severity":"WARN","message":"b"}`,
+		`{"severity":"WARN","message":"a","repeat_count":1}`,
+	}, inner.Lines())
+}
+
+// gelfHandler writes the level as a number and the message as short_message like the gelf handler
+type gelfHandler struct {
+	recordHandler
+}
+
+func (h *gelfHandler) BeforeWriting(e *log.Entry) error {
+	e.Int("level", int(e.Level)).Str("short_message", e.Message)
+	e.Message = ""
+	return nil
+}
+
+func TestDedupGelfStyleHandler(t *testing.T) {
+	inner := &gelfHandler{}
+	h := New(inner, Config{Window: time.Hour})
+
+	logger := log.New()
+	logger.AddHandler(h, log.AllLevels...)
+
+	logger.Warn("db down")
+	logger.Warn("completely different failure")
+	logger.Warn("db down")
+	assert.NoError(t, h.Flush())
+
+	assert.Equal(t, []string{
+		`{"level":2,"short_message":"db down"}`,
+		`{"level":2,"short_message":"completely different failure"}`,
+		`{"level":2,"short_message":"db down","repeat_count":1}`,
+	}, inner.Lines())
+}
@@ -15,7 +15,7 @@ func New() log.Handler {
 
 // BeforeWriting implements log.Handler.
 func (h *Handler) BeforeWriting(e *log.Entry) error {
-	e.Str("level", e.Level.String())
+	e.Str(e.FieldNames().Level, e.Level.String())
 
 	return nil
 }
@@ -0,0 +1,186 @@
+// Package filter implements a handler wrapper which only writes the entries matched by a predicate,
+// so entries can be routed or dropped by their content, e.g. only entries with component=payments.
+package filter
+
+import (
+	"bytes"
+	"encoding/json"
+	"reflect"
+	"regexp"
+
+	"github.com/jasonsoft/log/v2"
+)
+
+// Record is the entry which is passed to the predicate.  The predicate runs before the wrapped
+// handler changes the entry, so Level and Message are the entry's even if the handler writes
+// them differently, e.g. the gelf handler.
+type Record struct {
+	Level   log.Level
+	Message string
+	// Fields are the decoded fields without the level and message fields.  Numbers are json.Number.
+	Fields map[string]interface{}
+}
+
+// Predicate reports whether the entry should be written
+type Predicate func(r *Record) bool
+
+// Handler implementation.
+type Handler struct {
+	handler   log.Handler
+	predicate Predicate
+}
+
+// New creates a new filter handler which writes the entries matched by the predicate to the handler
+func New(handler log.Handler, predicate Predicate) *Handler {
+	return &Handler{
+		handler:   handler,
+		predicate: predicate,
+	}
+}
+
+// BeforeWriting implements log.Handler.  The entries which aren't matched by the predicate are dropped.
+func (h *Handler) BeforeWriting(e *log.Entry) error {
+	r := &Record{
+		Level:   e.Level,
+		Message: e.Message,
+		Fields:  decodeFields(e.JSON()),
+	}
+	names := e.FieldNames()
+	delete(r.Fields, names.Level)
+	delete(r.Fields, names.Message)
+
+	if !h.predicate(r) {
+		return log.ErrDropEntry
+	}
+	return h.handler.BeforeWriting(e)
+}
+
+// Write implements log.Handler.
+func (h *Handler) Write(bytes []byte) error {
+	return h.handler.Write(bytes)
+}
+
+// WriteFieldNames implements log.FieldNamesWriter.
+func (h *Handler) WriteFieldNames(bytes []byte, names log.FieldNames) error {
+	return log.WriteFieldNames(h.handler, bytes, names)
+}
+
+// Flush flushes the underlying handler if it implements log.Flusher.
+func (h *Handler) Flush() error {
+	flusher, ok := h.handler.(log.Flusher)
+	if ok {
+		return flusher.Flush()
+	}
+	return nil
+}
+
+// Close flushes the handler, then closes the underlying handler if it implements log.Closer.
+func (h *Handler) Close() error {
+	err := h.Flush()
+
+	closer, ok := h.handler.(log.Closer)
+	if ok {
+		closeErr := closer.Close()
+		if err == nil {
+			err = closeErr
+		}
+	}
+	return err
+}
+
+// decodeFields decodes the fields of the entry.  Numbers are json.Number.
+func decodeFields(b []byte) map[string]interface{} {
+	fields := map[string]interface{}{}
+	dec := json.NewDecoder(bytes.NewReader(b))
+	dec.UseNumber()
+	_ = dec.Decode(&fields)
+	return fields
+}
+
+// FieldEquals matches the entries whose field equals the value.  Numbers are compared by value,
+// so FieldEquals("status", 500) matches {"status":500}.
+func FieldEquals(key string, val interface{}) Predicate {
+	return func(r *Record) bool {
+		field, ok := r.Fields[key]
+		if !ok {
+			return false
+		}
+		if n, ok := field.(json.Number); ok {
+			return numberEquals(n, val)
+		}
+		return reflect.DeepEqual(field, val)
+	}
+}
+
+// FieldExists matches the entries which have the field
+func FieldExists(key string) Predicate {
+	return func(r *Record) bool {
+		_, ok := r.Fields[key]
+		return ok
+	}
+}
+
+// MessageMatches matches the entries whose message matches the regular expression
+func MessageMatches(re *regexp.Regexp) Predicate {
+	return func(r *Record) bool {
+		return re.MatchString(r.Message)
+	}
+}
+
+// LevelRange matches the entries whose level is between min and max inclusively
+func LevelRange(min, max log.Level) Predicate {
+	return func(r *Record) bool {
+		return r.Level >= min && r.Level <= max
+	}
+}
+
+// And matches the entries which are matched by all predicates
+func And(predicates ...Predicate) Predicate {
+	return func(r *Record) bool {
+		for _, p := range predicates {
+			if !p(r) {
+				return false
+			}
+		}
+		return true
+	}
+}
+
+// Or matches the entries which are matched by any predicate
+func Or(predicates ...Predicate) Predicate {
+	return func(r *Record) bool {
+		for _, p := range predicates {
+			if p(r) {
+				return true
+			}
+		}
+		return false
+	}
+}
+
+// Not matches the entries which aren't matched by the predicate
+func Not(predicate Predicate) Predicate {
+	return func(r *Record) bool {
+		return !predicate(r)
+	}
+}
+
+func numberEquals(n json.Number, val interface{}) bool {
+	if val, ok := val.(json.Number); ok {
+		return n == val
+	}
+
+	v := reflect.ValueOf(val)
+	switch v.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		i, err := n.Int64()
+		return err == nil && i == v.Int()
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		i, err := n.Int64()
+		return err == nil && i >= 0 && uint64(i) == v.Uint()
+	case reflect.Float32, reflect.Float64:
+		f, err := n.Float64()
+		return err == nil && f == v.Float()
+	}
+	return false
+}
@@ -0,0 +1,115 @@
+package filter
+
+import (
+	"regexp"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/jasonsoft/log/v2"
+	"github.com/stretchr/testify/assert"
+)
+
+type recordHandler struct {
+	mu    sync.Mutex
+	lines []string
+}
+
+func (h *recordHandler) BeforeWriting(e *log.Entry) error {
+	e.Str(e.FieldNames().Level, e.Level.String())
+	return nil
+}
+
+func (h *recordHandler) Write(bytes []byte) error {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	h.lines = append(h.lines, strings.TrimSpace(string(bytes)))
+	return nil
+}
+
+func TestFieldEquals(t *testing.T) {
+	inner := &recordHandler{}
+	logger := log.New()
+	logger.AddHandler(New(inner, FieldEquals("component", "payments")), log.AllLevels...)
+
+	logger.Str("component", "payments").Info("charged")
+	logger.Str("component", "users").Info("signed up")
+	logger.Info("started")
+
+	assert.Equal(t, []string{
+		`{"component":"payments","level":"INFO","msg":"charged"}`,
+	}, inner.lines)
+}
+
+func TestFieldEqualsNumber(t *testing.T) {
+	r := &Record{Fields: decodeFields([]byte(`{"status":500,"ratio":0.5,"ok":true}`))}
+
+	assert.True(t, FieldEquals("status", 500)(r))
+	assert.True(t, FieldEquals("status", uint16(500))(r))
+	assert.False(t, FieldEquals("status", "500")(r))
+	assert.True(t, FieldEquals("ratio", 0.5)(r))
+	assert.True(t, FieldEquals("ok", true)(r))
+	assert.False(t, FieldEquals("missing", nil)(r))
+}
+
+func TestMatchers(t *testing.T) {
+	inner := &recordHandler{}
+	logger := log.New()
+	predicate := Or(
+		And(FieldExists("user_id"), LevelRange(log.WarnLevel, log.FatalLevel)),
+		Not(MessageMatches(regexp.MustCompile(`^GET /health`))),
+	)
+	logger.AddHandler(New(inner, predicate), log.AllLevels...)
+
+	logger.Info("GET /health")
+	logger.Str("user_id", "1").Info("GET /health")
+	logger.Str("user_id", "1").Warn("GET /health")
+	logger.Info("GET /users")
+
+	assert.Equal(t, []string{
+		`{"user_id":"1","level":"WARN","msg":"GET /health"}`,
+		`{"level":"INFO","msg":"GET /users"}`,
+	}, inner.lines)
+}
+
+func TestFieldNames(t *testing.T) {
+	inner := &recordHandler{}
+	logger := log.New()
+	names := log.DefaultFieldNames()
+	names.Message = "message"
+	names.Level = "severity"
+	logger.SetFieldNames(names)
+	logger.AddHandler(New(inner, And(LevelRange(log.ErrorLevel, log.ErrorLevel), MessageMatches(regexp.MustCompile("failed")))), log.AllLevels...)
+
+	logger.Warn("failed")
+	logger.Error("failed")
+
+	assert.Len(t, inner.lines, 1)
+	assert.Contains(t, inner.lines[0], `"severity":"ERROR"`)
+}
+
+// gelfHandler writes the level as a number and the message as short_message like the gelf handler
+type gelfHandler struct {
+	recordHandler
+}
+
+func (h *gelfHandler) BeforeWriting(e *log.Entry) error {
+	e.Int("level", int(e.Level)).Str("short_message", e.Message)
+	e.Message = ""
+	return nil
+}
+
+func TestGelfStyleHandler(t *testing.T) {
+	inner := &gelfHandler{}
+	logger := log.New()
+	predicate := And(LevelRange(log.WarnLevel, log.FatalLevel), MessageMatches(regexp.MustCompile("^db")))
+	logger.AddHandler(New(inner, predicate), log.AllLevels...)
+
+	logger.Info("db down")
+	logger.Warn("db down")
+	logger.Warn("cache down")
+
+	assert.Equal(t, []string{
+		`{"level":2,"short_message":"db down"}`,
+	}, inner.lines)
+}
@@ -46,7 +46,8 @@ func (g *Gelf) close() error {
 	return nil
 }
 
-// BeforeWriting handles the log entry
+// BeforeWriting handles the log entry.  The names of GELF fields are defined by
+// the GELF specification, so they don't follow the field names of the logger.
 func (g *Gelf) BeforeWriting(e *log.Entry) error {
 	e.Str("version", "1.1").
 		Uint8("level", gelfLevel(e.Level)).
@@ -96,13 +97,13 @@ func (g *Gelf) manageConnections() {
 	if strings.EqualFold(g.url.Scheme, "tcp") {
 		g.conn, err = net.Dial("tcp", g.url.Host)
 		if err != nil {
-			stdlog.Println("gelf tcp connection was failed:", err.Error())
+			log.ReportError("gelf: tcp connection failed: %v", err)
 		}
 		g.bufferedWriter = bufio.NewWriter(g.conn)
 	} else {
 		g.conn, err = net.Dial("udp", g.url.Host)
 		if err != nil {
-			stdlog.Println("gelf udp connection was failed:", err.Error())
+			log.ReportError("gelf: udp connection failed: %v", err)
 		}
 		g.bufferedWriter = bufio.NewWriter(g.conn)
 	}
@@ -118,7 +119,7 @@ func (g *Gelf) manageConnections() {
 				// TODO: tcp is hard-code at the point, we need to remove that later
 				newConn, err := net.Dial("tcp", g.url.Host)
 				if err != nil {
-					stdlog.Printf("gelf: create connection failed: %v", err)
+					log.ReportError("gelf: create connection failed: %v", err)
 					continue
 				}
 				g.conn = newConn
@@ -17,13 +17,13 @@ type Handler struct {
 // New handler.
 func New() *Handler {
 	return &Handler{
-		Out: make([]byte, 500),
+		Out: make([]byte, 0, 500),
 	}
 }
 
 // BeforeWriting implements log.Handler.
 func (h *Handler) BeforeWriting(e *log.Entry) error {
-	e.Str("level", e.Level.String())
+	e.Str(e.FieldNames().Level, e.Level.String())
 
 	return nil
 }
@@ -32,12 +32,15 @@ func (h *Handler) BeforeWriting(e *log.Entry) error {
 func (h *Handler) Write(bytes []byte) error {
 	h.mu.Lock()
 	defer h.mu.Unlock()
-	h.Out = bytes
+	// bytes belongs to a pooled entry which will be reused after writing, so we need to copy it
+	h.Out = append(h.Out[:0], bytes...)
 	return nil
 }
 
 // Flush clear all buffer
 func (h *Handler) Flush() error {
+	h.mu.Lock()
+	defer h.mu.Unlock()
 	h.Out = []byte{}
 	return nil
 }
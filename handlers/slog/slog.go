@@ -0,0 +1,158 @@
+//go:build go1.21
+// +build go1.21
+
+// Package slog implements a handler which forwards entries into a slog.Handler, so the entries
+// can be written by any slog.Handler, e.g. slog.NewTextHandler.
+package slog
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	stdslog "log/slog"
+	"time"
+
+	"github.com/jasonsoft/log/v2"
+)
+
+// Handler implementation.
+type Handler struct {
+	handler stdslog.Handler
+}
+
+// New creates a new handler which forwards entries into the slog handler
+func New(handler stdslog.Handler) *Handler {
+	return &Handler{
+		handler: handler,
+	}
+}
+
+// BeforeWriting implements log.Handler.
+func (h *Handler) BeforeWriting(e *log.Entry) error {
+	e.Str(e.FieldNames().Level, e.Level.String())
+	return nil
+}
+
+// Write implements log.Handler for the entries which have the default field names.
+func (h *Handler) Write(b []byte) error {
+	return h.WriteFieldNames(b, log.DefaultFieldNames())
+}
+
+// WriteFieldNames implements log.FieldNamesWriter.  The level, message and timestamp fields are
+// converted into the record, and the other fields become the attributes of the record.  Nested
+// objects become groups.
+func (h *Handler) WriteFieldNames(b []byte, names log.FieldNames) error {
+	dec := json.NewDecoder(bytes.NewReader(b))
+	dec.UseNumber()
+
+	// skip the begin marker
+	_, err := dec.Token()
+	if err != nil {
+		return err
+	}
+	attrs, err := decodeAttrs(dec)
+	if err != nil {
+		return err
+	}
+
+	level := stdslog.LevelInfo
+	msg := ""
+	t := time.Now()
+	rest := attrs[:0]
+	for _, a := range attrs {
+		switch a.Key {
+		case names.Level:
+			lvl, err := log.ParseLevel(a.Value.String())
+			if err == nil {
+				level = log.ToSlogLevel(lvl)
+			}
+		case names.Message:
+			msg = a.Value.String()
+		case names.Time:
+			parsed, err := time.Parse(time.RFC3339Nano, a.Value.String())
+			if err != nil {
+				rest = append(rest, a)
+				continue
+			}
+			t = parsed
+		default:
+			rest = append(rest, a)
+		}
+	}
+
+	ctx := context.Background()
+	if !h.handler.Enabled(ctx, level) {
+		return nil
+	}
+
+	r := stdslog.NewRecord(t, level, msg, 0)
+	r.AddAttrs(rest...)
+	return h.handler.Handle(ctx, r)
+}
+
+// decodeAttrs decodes the fields of a JSON object until its end marker
+func decodeAttrs(dec *json.Decoder) ([]stdslog.Attr, error) {
+	var attrs []stdslog.Attr
+	for dec.More() {
+		token, err := dec.Token()
+		if err != nil {
+			return nil, err
+		}
+		key, _ := token.(string)
+
+		value, err := decodeValue(dec)
+		if err != nil {
+			return nil, err
+		}
+		attrs = append(attrs, stdslog.Attr{Key: key, Value: value})
+	}
+
+	// skip the end marker
+	_, err := dec.Token()
+	return attrs, err
+}
+
+func decodeValue(dec *json.Decoder) (stdslog.Value, error) {
+	token, err := dec.Token()
+	if err != nil {
+		return stdslog.Value{}, err
+	}
+
+	switch v := token.(type) {
+	case json.Delim:
+		if v == '{' {
+			attrs, err := decodeAttrs(dec)
+			if err != nil {
+				return stdslog.Value{}, err
+			}
+			return stdslog.GroupValue(attrs...), nil
+		}
+
+		var vals []interface{}
+		for dec.More() {
+			var val interface{}
+			err = dec.Decode(&val)
+			if err != nil {
+				return stdslog.Value{}, err
+			}
+			vals = append(vals, val)
+		}
+		_, err = dec.Token()
+		return stdslog.AnyValue(vals), err
+	case string:
+		return stdslog.StringValue(v), nil
+	case bool:
+		return stdslog.BoolValue(v), nil
+	case json.Number:
+		if i, err := v.Int64(); err == nil {
+			return stdslog.Int64Value(i), nil
+		}
+		f, err := v.Float64()
+		if err != nil {
+			return stdslog.StringValue(v.String()), nil
+		}
+		return stdslog.Float64Value(f), nil
+	default:
+		return stdslog.AnyValue(nil), nil
+	}
+}
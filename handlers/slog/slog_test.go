@@ -0,0 +1,80 @@
+//go:build go1.21
+// +build go1.21
+
+package slog
+
+import (
+	"bytes"
+	"context"
+	stdslog "log/slog"
+	"testing"
+
+	"github.com/jasonsoft/log/v2"
+	"github.com/jasonsoft/log/v2/handlers/async"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestForward(t *testing.T) {
+	var out bytes.Buffer
+	inner := stdslog.NewJSONHandler(&out, &stdslog.HandlerOptions{
+		ReplaceAttr: func(groups []string, a stdslog.Attr) stdslog.Attr {
+			if len(groups) == 0 && a.Key == stdslog.TimeKey {
+				return stdslog.Attr{}
+			}
+			return a
+		},
+	})
+
+	logger := log.New()
+	logger.AddHandler(New(inner), log.AllLevels...)
+
+	logger.Str("app", "santa").Int("count", 2).Float64("ratio", 0.5).Strs("tags", []string{"a", "b"}).Warn("hello")
+	assert.Equal(t, `{"level":"WARN","msg":"hello","app":"santa","count":2,"ratio":0.5,"tags":["a","b"]}`+"\n", out.String())
+
+	out.Reset()
+	logger.FromContext(context.Background()).Interface("user", map[string]interface{}{"id": 1}).Debug("debug")
+	assert.Equal(t, "", out.String())
+
+	logger.FromContext(context.Background()).Interface("user", map[string]interface{}{"id": 1}).Info("nested")
+	assert.Equal(t, `{"level":"INFO","msg":"nested","user":{"id":1}}`+"\n", out.String())
+}
+
+func TestForwardLevels(t *testing.T) {
+	var out bytes.Buffer
+	inner := stdslog.NewTextHandler(&out, &stdslog.HandlerOptions{Level: stdslog.LevelDebug})
+
+	logger := log.New()
+	logger.AddHandler(New(inner), log.AllLevels...)
+	log.AutoStaceTrace = false
+	defer func() { log.AutoStaceTrace = true }()
+
+	logger.Error("error")
+	assert.Contains(t, out.String(), "level=ERROR msg=error")
+}
+
+func TestSharedByLoggers(t *testing.T) {
+	var out bytes.Buffer
+	inner := stdslog.NewTextHandler(&out, &stdslog.HandlerOptions{
+		ReplaceAttr: func(groups []string, a stdslog.Attr) stdslog.Attr {
+			if len(groups) == 0 && a.Key == stdslog.TimeKey {
+				return stdslog.Attr{}
+			}
+			return a
+		},
+	})
+
+	// the field names of each entry are passed through the async handler
+	h := async.New(New(inner), async.Config{})
+	logger1 := log.New()
+	logger1.AddHandler(h, log.AllLevels...)
+	logger2 := log.New()
+	logger2.SetFieldNames(log.FieldNames{Level: "severity", Message: "message"})
+	logger2.AddHandler(h, log.AllLevels...)
+
+	logger1.Warn("one")
+	logger2.Warn("two")
+	logger1.Info("three")
+	assert.NoError(t, h.Flush())
+
+	assert.Equal(t, "level=WARN msg=one\nlevel=WARN msg=two\nlevel=INFO msg=three\n", out.String())
+}
@@ -0,0 +1,67 @@
+package log
+
+import (
+	"encoding/json"
+	"net/http"
+)
+
+type levelPayload struct {
+	Level *Level `json:"level"`
+}
+
+type levelErrorPayload struct {
+	Error string `json:"error"`
+}
+
+type levelHTTPHandler struct {
+	logger *Logger
+}
+
+// LevelHTTPHandler returns a http handler which reports and changes the minimum level of the logger.
+//
+// GET responds with the current level, e.g. {"level":"INFO"}.
+// PUT changes the level with the same JSON payload, e.g. {"level":"debug"}.
+func (l *Logger) LevelHTTPHandler() http.Handler {
+	return levelHTTPHandler{logger: l}
+}
+
+// LevelHTTPHandler returns a http handler which reports and changes the minimum level of the default logger
+func LevelHTTPHandler() http.Handler {
+	return _logger.LevelHTTPHandler()
+}
+
+// ServeHTTP implements http.Handler.
+func (h levelHTTPHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+
+	switch r.Method {
+	case http.MethodGet:
+	case http.MethodPut:
+		var req levelPayload
+		err := json.NewDecoder(r.Body).Decode(&req)
+		if err != nil {
+			writeLevelError(w, http.StatusBadRequest, "log: decode level payload: "+err.Error())
+			return
+		}
+		if req.Level == nil {
+			writeLevelError(w, http.StatusBadRequest, "log: level is required")
+			return
+		}
+		if err := h.logger.SetLevel(*req.Level); err != nil {
+			writeLevelError(w, http.StatusBadRequest, err.Error())
+			return
+		}
+	default:
+		w.Header().Set("Allow", "GET, PUT")
+		writeLevelError(w, http.StatusMethodNotAllowed, "log: only GET and PUT are supported")
+		return
+	}
+
+	level := h.logger.Level()
+	_ = json.NewEncoder(w).Encode(levelPayload{Level: &level})
+}
+
+func writeLevelError(w http.ResponseWriter, code int, msg string) {
+	w.WriteHeader(code)
+	_ = json.NewEncoder(w).Encode(levelErrorPayload{Error: msg})
+}
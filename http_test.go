@@ -0,0 +1,55 @@
+package log_test
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/jasonsoft/log/v2"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestLevelHTTPHandler(t *testing.T) {
+	logger := log.New()
+	logger.SetLevel(log.InfoLevel)
+	h := logger.LevelHTTPHandler()
+
+	t.Run("get level", func(t *testing.T) {
+		w := httptest.NewRecorder()
+		h.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/log/level", nil))
+		assert.Equal(t, http.StatusOK, w.Code)
+		assert.Equal(t, `{"level":"INFO"}`+"\n", w.Body.String())
+	})
+
+	t.Run("put level", func(t *testing.T) {
+		w := httptest.NewRecorder()
+		h.ServeHTTP(w, httptest.NewRequest(http.MethodPut, "/log/level", strings.NewReader(`{"level":"debug"}`)))
+		assert.Equal(t, http.StatusOK, w.Code)
+		assert.Equal(t, `{"level":"DEBUG"}`+"\n", w.Body.String())
+		assert.Equal(t, log.DebugLevel, logger.Level())
+	})
+
+	t.Run("put invalid level", func(t *testing.T) {
+		w := httptest.NewRecorder()
+		h.ServeHTTP(w, httptest.NewRequest(http.MethodPut, "/log/level", strings.NewReader(`{"level":"verbose"}`)))
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+		assert.Equal(t, log.DebugLevel, logger.Level())
+
+		w = httptest.NewRecorder()
+		h.ServeHTTP(w, httptest.NewRequest(http.MethodPut, "/log/level", strings.NewReader(`{}`)))
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+
+		w = httptest.NewRecorder()
+		h.ServeHTTP(w, httptest.NewRequest(http.MethodPut, "/log/level", strings.NewReader(`{"level":"trace"}`)))
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+		assert.Equal(t, log.DebugLevel, logger.Level())
+	})
+
+	t.Run("method not allowed", func(t *testing.T) {
+		w := httptest.NewRecorder()
+		h.ServeHTTP(w, httptest.NewRequest(http.MethodPost, "/log/level", nil))
+		assert.Equal(t, http.StatusMethodNotAllowed, w.Code)
+		assert.Equal(t, "GET, PUT", w.Header().Get("Allow"))
+	})
+}
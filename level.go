@@ -1,6 +1,10 @@
 package log
 
-import "strings"
+import (
+	"errors"
+	"fmt"
+	"strings"
+)
 
 // Level of the log
 type Level uint8
@@ -16,6 +20,10 @@ const (
 	TraceLevel
 )
 
+// ErrInvalidMinLevel is returned when a level can't be the minimum level, e.g. TraceLevel
+// which is only the level of trace entries and sorts above FatalLevel
+var ErrInvalidMinLevel = errors.New("log: invalid minimum level")
+
 // AllLevels is an array of all log levels, for easier registering of all levels to a handler
 var AllLevels = []Level{
 	DebugLevel,
@@ -42,6 +50,42 @@ func (p Level) String() string {
 	return levelNames[p]
 }
 
+// MarshalText implements encoding.TextMarshaler.
+func (p Level) MarshalText() ([]byte, error) {
+	if int(p) >= len(levelNames) {
+		return nil, fmt.Errorf("log: unknown level %d", p)
+	}
+	return []byte(levelNames[p]), nil
+}
+
+// UnmarshalText implements encoding.TextUnmarshaler.
+func (p *Level) UnmarshalText(text []byte) error {
+	level, err := ParseLevel(string(text))
+	if err != nil {
+		return err
+	}
+	*p = level
+	return nil
+}
+
+// ParseLevel returns the level of the name. The name is case-insensitive.
+func ParseLevel(name string) (Level, error) {
+	for i, levelName := range levelNames {
+		if strings.EqualFold(name, levelName) {
+			return Level(i), nil
+		}
+	}
+	return DebugLevel, fmt.Errorf("log: unknown level %q", name)
+}
+
+// validMinLevel returns ErrInvalidMinLevel unless the level is between DebugLevel and FatalLevel
+func validMinLevel(level Level) error {
+	if level > FatalLevel {
+		return fmt.Errorf("%w: %d", ErrInvalidMinLevel, level)
+	}
+	return nil
+}
+
 // GetLevelsFromMinLevel returns Levels array which above minLevel
 func GetLevelsFromMinLevel(minLevel string) []Level {
 	minLevel = strings.ToLower(minLevel)
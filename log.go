@@ -2,16 +2,15 @@ package log
 
 import (
 	"context"
+	"errors"
 	"fmt"
-	stdlog "log"
-	"runtime"
-	"strings"
-	"sync"
+	"net"
+	"time"
 )
 
-// Logger is the default instance of the log package
+// _logger is the default instance of the log package
 var (
-	_logger = new()
+	_logger = New()
 
 	// ErrorHandler is called whenever handler fails to write an event on its
 	// output. If not set, an error is printed on the stderr. This handler must
@@ -23,280 +22,425 @@ var (
 	AutoStaceTrace = true
 )
 
-// Handler is an interface that log handlers need to be implemented
+// Handler is an interface that log handlers need to be implemented.  BeforeWriting can return
+// ErrDropEntry, so the entry isn't written by the handler, e.g. a filter which wraps a handler.
 type Handler interface {
 	BeforeWriting(*Entry) error
 	Write([]byte) error
 }
 
+// FieldNamesWriter is implemented by handlers which decode the bytes of the entries, so they know
+// the field names of the logger which wrote each entry.  A handler can be shared by loggers with
+// different field names, so the names must not be kept between entries.  The logger calls
+// WriteFieldNames instead of Write.
+type FieldNamesWriter interface {
+	WriteFieldNames(bytes []byte, names FieldNames) error
+}
+
+// WriteFieldNames writes the bytes of an entry by WriteFieldNames if the handler implements
+// FieldNamesWriter, otherwise by Write.  Handlers which wrap other handlers use it to pass the
+// field names on.
+func WriteFieldNames(handler Handler, bytes []byte, names FieldNames) error {
+	if w, ok := handler.(FieldNamesWriter); ok {
+		return w.WriteFieldNames(bytes, names)
+	}
+	return handler.Write(bytes)
+}
+
 // Flusher is an interface that allow handles have the ability to clear buffer and close connection
 type Flusher interface {
 	Flush() error
 }
 
-// Hookfunc is an func that allow us to do something before writing
+// Hookfunc is an func that allow us to do something before writing.  A hook can add
+// fields or change the level of the entry, and it drops the entry by returning ErrDropEntry.
+// Other errors are reported to ErrorHandler and the entry is still written.
 type Hookfunc func(*Entry) error
 
-type logger struct {
-	handles              []Handler
-	hooks                []Hookfunc
-	leveledHandlers      map[Level][]Handler
-	cacheLeveledHandlers func(level Level) []Handler
-	rwMutex              sync.RWMutex
-	buf                  []byte
-}
+// ErrDropEntry is returned by a hook to drop the entry, or by BeforeWriting of a handler to skip Write
+var ErrDropEntry = errors.New("log: drop entry")
 
-func new() *logger {
-	logger := logger{
-		leveledHandlers: map[Level][]Handler{},
-	}
-
-	logger.cacheLeveledHandlers = logger.getLeveledHandlers()
-	return &logger
-}
-
-func (l *logger) getLeveledHandlers() func(level Level) []Handler {
-	debugHandlers := l.leveledHandlers[DebugLevel]
-	infoHandlers := l.leveledHandlers[InfoLevel]
-	warnHandlers := l.leveledHandlers[WarnLevel]
-	errorHandlers := l.leveledHandlers[ErrorLevel]
-	panicHandlers := l.leveledHandlers[PanicLevel]
-	fatalHandlers := l.leveledHandlers[FatalLevel]
-
-	return func(level Level) []Handler {
-		switch level {
-		case DebugLevel:
-			return debugHandlers
-		case InfoLevel:
-			return infoHandlers
-		case WarnLevel:
-			return warnHandlers
-		case ErrorLevel:
-			return errorHandlers
-		case PanicLevel:
-			return panicHandlers
-		case FatalLevel:
-			return fatalHandlers
-		}
-
-		return []Handler{}
-	}
+// Default returns the default logger which is used by the package-level functions
+func Default() *Logger {
+	return _logger
 }
 
-// AddHandler adds a new Log Handler and specifies what log levels
+// AddHandler adds a new Log Handler to the default logger and specifies what log levels
 // the handler will be passed log entries for
 func AddHandler(handler Handler, levels ...Level) {
-	_logger.rwMutex.Lock()
-	defer _logger.rwMutex.Unlock()
+	_logger.AddHandler(handler, levels...)
+}
 
-	for _, level := range levels {
-		_logger.leveledHandlers[level] = append(_logger.leveledHandlers[level], handler)
-	}
+// AddNamedHandler adds a new Log Handler with a name to the default logger
+func AddNamedHandler(name string, handler Handler, levels ...Level) error {
+	return _logger.AddNamedHandler(name, handler, levels...)
+}
 
-	_logger.handles = append(_logger.handles, handler)
-	_logger.cacheLeveledHandlers = _logger.getLeveledHandlers()
+// RemoveHandler removes the handler from the default logger and flushes it
+func RemoveHandler(handler Handler) error {
+	return _logger.RemoveHandler(handler)
 }
 
-// RemoveAllHandlers removes all handlers
+// RemoveHandlerByName removes the handler of the name from the default logger and flushes it
+func RemoveHandlerByName(name string) error {
+	return _logger.RemoveHandlerByName(name)
+}
+
+// ReplaceHandler swaps the old handler of the default logger with the new handler
+func ReplaceHandler(oldHandler Handler, newHandler Handler) error {
+	return _logger.ReplaceHandler(oldHandler, newHandler)
+}
+
+// ReplaceHandlerByName swaps the handler of the name of the default logger with the new handler
+func ReplaceHandlerByName(name string, handler Handler) error {
+	return _logger.ReplaceHandlerByName(name, handler)
+}
+
+// Handlers returns the registered handlers of the default logger and their levels
+func Handlers() []HandlerInfo {
+	return _logger.Handlers()
+}
+
+// RemoveAllHandlers removes all handlers of the default logger
 func RemoveAllHandlers() {
-	_logger.rwMutex.Lock()
-	defer _logger.rwMutex.Unlock()
+	_logger.RemoveAllHandlers()
+}
+
+// SetLevel changes the minimum level of the default logger at runtime
+func SetLevel(level Level) error {
+	return _logger.SetLevel(level)
+}
+
+// SetHandlerLevel changes the minimum level of a handler which is registered on the default logger
+func SetHandlerLevel(handler Handler, level Level) error {
+	return _logger.SetHandlerLevel(handler, level)
+}
 
-	_logger.leveledHandlers = map[Level][]Handler{}
-	_logger.handles = []Handler{}
-	_logger.hooks = []Hookfunc{}
-	_logger.cacheLeveledHandlers = _logger.getLeveledHandlers()
+// SetSampler sets the sampler of the default logger.  A nil sampler disables sampling.
+func SetSampler(sampler *Sampler) {
+	_logger.SetSampler(sampler)
+}
+
+// SetCaller configures the caller field of the default logger
+func SetCaller(config CallerConfig) {
+	_logger.SetCaller(config)
+}
+
+// SetTimestamp configures the timestamp field of the default logger
+func SetTimestamp(config TimestampConfig) {
+	_logger.SetTimestamp(config)
+}
+
+// SetFilter configures the minimum level of the default logger and its named contexts by
+// comma-separated directives, e.g. "info,db=debug,db.pool=warn"
+func SetFilter(directives string) error {
+	return _logger.SetFilter(directives)
+}
+
+// SetFilterFromEnv configures the default logger by the directives in the environment variable
+func SetFilterFromEnv(key string) error {
+	return _logger.SetFilterFromEnv(key)
+}
+
+// Named returns a blank context of the default logger with the name
+func Named(name string) Context {
+	return _logger.Named(name)
+}
+
+// Close removes all handlers from the default logger, then flushes and closes them
+func Close(ctx context.Context) error {
+	return _logger.Close(ctx)
+}
+
+// AddExitHook adds a func which is called before Fatal of the default logger exits the process
+func AddExitHook(hook func()) {
+	_logger.AddExitHook(hook)
 }
 
 // AddHook adds a new Hook to log entry
 func AddHook(hook Hookfunc) error {
-	_logger.rwMutex.Lock()
-	defer _logger.rwMutex.Unlock()
+	return _logger.AddHook(hook)
+}
 
-	_logger.hooks = append(_logger.hooks, hook)
-	return nil
+// AddHandlerHook adds a new Hook which only runs for the handler of the default logger
+func AddHandlerHook(handler Handler, hook Hookfunc) error {
+	return _logger.AddHandlerHook(handler, hook)
 }
 
 // Debug level formatted message
 func Debug(msg string) {
-	e := newEntry(_logger, _logger.buf)
-	e.Debug(msg)
+	_logger.newEntry(1).Debug(msg)
 }
 
 // Debugf level formatted message
 func Debugf(msg string, v ...interface{}) {
-	e := newEntry(_logger, _logger.buf)
-	e.Debugf(msg, v...)
+	_logger.newEntry(1).Debugf(msg, v...)
 }
 
 // Info level formatted message
 func Info(msg string) {
-	e := newEntry(_logger, _logger.buf)
-	e.Info(msg)
+	_logger.newEntry(1).Info(msg)
 }
 
 // Infof level formatted message
 func Infof(msg string, v ...interface{}) {
-	e := newEntry(_logger, _logger.buf)
-	e.Infof(msg, v...)
+	_logger.newEntry(1).Infof(msg, v...)
 }
 
 // Warn level formatted message
 func Warn(msg string) {
-	e := newEntry(_logger, _logger.buf)
-	e.Warn(msg)
+	_logger.newEntry(1).Warn(msg)
 }
 
 // Warnf level formatted message
 func Warnf(msg string, v ...interface{}) {
-	e := newEntry(_logger, _logger.buf)
-	e.Warnf(msg, v...)
+	_logger.newEntry(1).Warnf(msg, v...)
 }
 
 // Error level formatted message
 func Error(msg string) {
-	e := newEntry(_logger, _logger.buf)
-	e.Error(msg)
+	_logger.newEntry(1).Error(msg)
 }
 
 // Errorf level formatted message
 func Errorf(msg string, v ...interface{}) {
-	e := newEntry(_logger, _logger.buf)
-	e.Errorf(msg, v...)
+	_logger.newEntry(1).Errorf(msg, v...)
 }
 
 // Panic level formatted message
 func Panic(msg string) {
-	e := newEntry(_logger, _logger.buf)
-	e.Panic(msg)
+	_logger.newEntry(1).Panic(msg)
 }
 
 // Panicf level formatted message
 func Panicf(msg string, v ...interface{}) {
-	e := newEntry(_logger, nil)
-	e.Panicf(msg, v...)
+	_logger.newEntry(1).Panicf(msg, v...)
 }
 
 // Fatal level formatted message, followed by an exit.
 func Fatal(msg string) {
-	e := newEntry(_logger, _logger.buf)
-	e.Fatal(msg)
+	_logger.newEntry(1).Fatal(msg)
 }
 
 // Fatalf level formatted message, followed by an exit.
 func Fatalf(msg string, v ...interface{}) {
-	e := newEntry(_logger, _logger.buf)
-	e.Fatalf(msg, v...)
+	_logger.newEntry(1).Fatalf(msg, v...)
 }
 
 // Str add string field to current context
 func Str(key string, val string) Context {
-	c := newContext(_logger)
-	return c.Str(key, val)
+	return _logger.Str(key, val)
 }
 
 // Bool add bool field to current context
 func Bool(key string, val bool) Context {
-	c := newContext(_logger)
-	return c.Bool(key, val)
+	return _logger.Bool(key, val)
 }
 
 // Int add Int field to current context
 func Int(key string, val int) Context {
-	c := newContext(_logger)
-	return c.Int(key, val)
+	return _logger.Int(key, val)
 }
 
 // Int8 add Int8 field to current context
 func Int8(key string, val int8) Context {
-	c := newContext(_logger)
-	return c.Int8(key, val)
+	return _logger.Int8(key, val)
 }
 
 // Int16 add Int16 field to current context
 func Int16(key string, val int16) Context {
-	c := newContext(_logger)
-	return c.Int16(key, val)
+	return _logger.Int16(key, val)
 }
 
 // Int32 add Int32 field to current context
 func Int32(key string, val int32) Context {
-	c := newContext(_logger)
-	return c.Int32(key, val)
+	return _logger.Int32(key, val)
 }
 
 // Int64 add Int64 field to current context
 func Int64(key string, val int64) Context {
-	c := newContext(_logger)
-	return c.Int64(key, val)
+	return _logger.Int64(key, val)
 }
 
 // Uint add Uint field to current context
 func Uint(key string, val uint) Context {
-	c := newContext(_logger)
-	return c.Uint(key, val)
+	return _logger.Uint(key, val)
 }
 
 // Uint8 add Uint8 field to current context
 func Uint8(key string, val uint8) Context {
-	c := newContext(_logger)
-	return c.Uint8(key, val)
+	return _logger.Uint8(key, val)
 }
 
 // Uint16 add Uint16 field to current context
 func Uint16(key string, val uint16) Context {
-	c := newContext(_logger)
-	return c.Uint16(key, val)
+	return _logger.Uint16(key, val)
 }
 
 // Uint32 add Uint32 field to current context
 func Uint32(key string, val uint32) Context {
-	c := newContext(_logger)
-	return c.Uint32(key, val)
+	return _logger.Uint32(key, val)
 }
 
 // Uint64 add Uint64 field to current context
 func Uint64(key string, val uint64) Context {
-	c := newContext(_logger)
-	return c.Uint64(key, val)
+	return _logger.Uint64(key, val)
 }
 
 // Float32 add float32 field to current context
 func Float32(key string, val float32) Context {
-	c := newContext(_logger)
-	return c.Float32(key, val)
+	return _logger.Float32(key, val)
 }
 
 // Float64 add Float64 field to current context
 func Float64(key string, val float64) Context {
-	c := newContext(_logger)
-	return c.Float64(key, val)
+	return _logger.Float64(key, val)
+}
+
+// Strs add string array field to current context
+func Strs(key string, val []string) Context {
+	return _logger.Strs(key, val)
+}
+
+// Ints add Int array field to current context
+func Ints(key string, val []int) Context {
+	return _logger.Ints(key, val)
+}
+
+// Dur adds Duration field to current context
+func Dur(key string, val time.Duration) Context {
+	return _logger.Dur(key, val)
+}
+
+// Time adds Time field to current context
+func Time(key string, val time.Time) Context {
+	return _logger.Time(key, val)
+}
+
+// Times adds Time array field to current context
+func Times(key string, val []time.Time) Context {
+	return _logger.Times(key, val)
+}
+
+// Interface adds the field key with i marshaled using reflection.
+func Interface(key string, val interface{}) Context {
+	return _logger.Interface(key, val)
+}
+
+// Stringer adds the string of the Stringer field to current context, or null if it is nil
+func Stringer(key string, val fmt.Stringer) Context {
+	return _logger.Stringer(key, val)
+}
+
+// Bools add bool array field to current context
+func Bools(key string, val []bool) Context {
+	return _logger.Bools(key, val)
+}
+
+// Ints8 add Int8 array field to current context
+func Ints8(key string, val []int8) Context {
+	return _logger.Ints8(key, val)
+}
+
+// Ints16 add Int16 array field to current context
+func Ints16(key string, val []int16) Context {
+	return _logger.Ints16(key, val)
+}
+
+// Ints32 add Int32 array field to current context
+func Ints32(key string, val []int32) Context {
+	return _logger.Ints32(key, val)
+}
+
+// Ints64 add Int64 array field to current context
+func Ints64(key string, val []int64) Context {
+	return _logger.Ints64(key, val)
+}
+
+// Uints add Uint array field to current context
+func Uints(key string, val []uint) Context {
+	return _logger.Uints(key, val)
+}
+
+// Uints8 add Uint8 array field to current context
+func Uints8(key string, val []uint8) Context {
+	return _logger.Uints8(key, val)
+}
+
+// Uints16 add Uint16 array field to current context
+func Uints16(key string, val []uint16) Context {
+	return _logger.Uints16(key, val)
+}
+
+// Uints32 add Uint32 array field to current context
+func Uints32(key string, val []uint32) Context {
+	return _logger.Uints32(key, val)
+}
+
+// Uints64 add Uint64 array field to current context
+func Uints64(key string, val []uint64) Context {
+	return _logger.Uints64(key, val)
+}
+
+// Floats32 add Float32 array field to current context
+func Floats32(key string, val []float32) Context {
+	return _logger.Floats32(key, val)
+}
+
+// Floats64 add Float64 array field to current context
+func Floats64(key string, val []float64) Context {
+	return _logger.Floats64(key, val)
+}
+
+// Bytes add bytes field as a string to current context
+func Bytes(key string, val []byte) Context {
+	return _logger.Bytes(key, val)
+}
+
+// Hex add bytes field as a hex string to current context
+func Hex(key string, val []byte) Context {
+	return _logger.Hex(key, val)
+}
+
+// Durs add Duration array field in milliseconds to current context
+func Durs(key string, val []time.Duration) Context {
+	return _logger.Durs(key, val)
+}
+
+// IPAddr add IPv4 or IPv6 address field to current context
+func IPAddr(key string, val net.IP) Context {
+	return _logger.IPAddr(key, val)
+}
+
+// IPPrefix add IPv4 or IPv6 prefix (address and mask) field to current context
+func IPPrefix(key string, val net.IPNet) Context {
+	return _logger.IPPrefix(key, val)
+}
+
+// MACAddr add MAC address field to current context
+func MACAddr(key string, val net.HardwareAddr) Context {
+	return _logger.MACAddr(key, val)
+}
+
+// RawJSON add already encoded JSON field to current context.  The value must be valid JSON
+func RawJSON(key string, val []byte) Context {
+	return _logger.RawJSON(key, val)
 }
 
 // Err add error field to current context
 func Err(err error) Context {
-	c := newContext(_logger)
-	return c.Err(err)
+	return _logger.Err(err)
 }
 
 // Flush clear all handler's buffer
 func Flush() {
-	for _, h := range _logger.handles {
-		flusher, ok := h.(Flusher)
-		if ok {
-			err := flusher.Flush()
-			if err != nil {
-				stdlog.Printf("log: flush log handler: %v", err)
-			}
-		}
-	}
+	_logger.Flush()
 }
 
 // Trace returns a new entry with a Stop method to fire off
 // a corresponding completion log, useful with defer.
 func Trace(msg string) *Entry {
-	e := newEntry(_logger, nil)
-	return e.Trace(msg)
+	return _logger.Trace(msg)
 }
 
 var (
@@ -314,29 +458,5 @@ func newStdContext(ctx context.Context, c Context) context.Context {
 
 // FromContext return a log context from the standard context
 func FromContext(ctx context.Context) Context {
-	v := ctx.Value(ctxKey)
-	if v == nil {
-		return newContext(_logger)
-	}
-
-	return v.(Context)
-}
-
-func getStackTrace() string {
-	stackBuf := make([]uintptr, 50)
-	length := runtime.Callers(3, stackBuf[:])
-	stack := stackBuf[:length]
-
-	var b strings.Builder
-	frames := runtime.CallersFrames(stack)
-	for {
-		frame, more := frames.Next()
-		if !strings.Contains(frame.File, "runtime/") {
-			_, _ = b.WriteString(fmt.Sprintf("\n\tFile: %s, Line: %d. Function: %s", frame.File, frame.Line, frame.Function))
-		}
-		if !more {
-			break
-		}
-	}
-	return b.String()
+	return _logger.FromContext(ctx)
 }
@@ -3,6 +3,7 @@ package log_test
 import (
 	"context"
 	"errors"
+	"net"
 	"sync"
 	"testing"
 	"time"
@@ -36,6 +37,35 @@ func TestAddHandlers(t *testing.T) {
 	assert.Equal(t, `{"level":"INFO","msg":"info"}`+"\n", string(h2.Out))
 }
 
+func TestNewLogger(t *testing.T) {
+	h1 := memory.New()
+	logger1 := log.New()
+	logger1.AddHandler(h1, log.AllLevels...)
+	logger1.Str("tenant", "a").SaveToDefault()
+
+	h2 := memory.New()
+	logger2 := log.New()
+	logger2.AddHandler(h2, log.AllLevels...)
+	logger2.AddHook(func(e *log.Entry) error {
+		e.Str("tenant", "b")
+		return nil
+	})
+
+	logger1.Info("hello")
+	assert.Equal(t, `{"tenant":"a","level":"INFO","msg":"hello"}`+"\n", string(h1.Out))
+
+	logger2.Str("app", "santa").Info("world")
+	assert.Equal(t, `{"app":"santa","tenant":"b","level":"INFO","msg":"world"}`+"\n", string(h2.Out))
+
+	logger1.RemoveAllHandlers()
+	logger1.Info("dropped")
+	assert.Equal(t, `{"tenant":"a","level":"INFO","msg":"hello"}`+"\n", string(h1.Out))
+
+	ctx := logger2.Str("request_id", "abc").WithContext(context.Background())
+	logger2.FromContext(ctx).Debug("from context")
+	assert.Equal(t, `{"request_id":"abc","tenant":"b","level":"DEBUG","msg":"from context"}`+"\n", string(h2.Out))
+}
+
 type ErrHandler struct {
 }
 
@@ -174,6 +204,22 @@ func TestContext(t *testing.T) {
 
 }
 
+func TestZeroContext(t *testing.T) {
+	h := memory.New()
+	log.AddHandler(h, log.AllLevels...)
+	defer func() {
+		_ = log.RemoveHandler(h)
+	}()
+
+	var c log.Context
+	c.Info("zero")
+	assert.Contains(t, string(h.Out), `"level":"INFO","msg":"zero"}`)
+
+	c.Err(errors.New("oops")).Errs("errs", []error{errors.New("a")}).Warn("zero err")
+	assert.Contains(t, string(h.Out), `"error":"oops","errs":["a"]`)
+	assert.Contains(t, string(h.Out), `"level":"WARN","msg":"zero err"}`)
+}
+
 func TestFlush(t *testing.T) {
 	log.RemoveAllHandlers()
 	h := memory.New()
@@ -209,6 +255,332 @@ func TestLevels(t *testing.T) {
 	assert.Equal(t, []log.Level{log.FatalLevel}, levels)
 }
 
+func TestParseLevel(t *testing.T) {
+	level, err := log.ParseLevel("warn")
+	assert.NoError(t, err)
+	assert.Equal(t, log.WarnLevel, level)
+
+	level, err = log.ParseLevel("DEBUG")
+	assert.NoError(t, err)
+	assert.Equal(t, log.DebugLevel, level)
+
+	_, err = log.ParseLevel("verbose")
+	assert.Error(t, err)
+}
+
+func TestSetLevel(t *testing.T) {
+	logger := log.New()
+	h := memory.New()
+	logger.AddHandler(h, log.AllLevels...)
+
+	logger.SetLevel(log.WarnLevel)
+	assert.Equal(t, log.WarnLevel, logger.Level())
+
+	logger.Info("info")
+	assert.Equal(t, "", string(h.Out))
+
+	logger.Warn("warn")
+	assert.Equal(t, `{"level":"WARN","msg":"warn"}`+"\n", string(h.Out))
+
+	logger.SetLevel(log.DebugLevel)
+	logger.Debug("debug")
+	assert.Equal(t, `{"level":"DEBUG","msg":"debug"}`+"\n", string(h.Out))
+
+	// trace level sorts above fatal level, so it can't be a minimum level
+	assert.True(t, errors.Is(logger.SetLevel(log.TraceLevel), log.ErrInvalidMinLevel))
+	assert.True(t, errors.Is(logger.SetLevel(log.Level(100)), log.ErrInvalidMinLevel))
+	assert.Equal(t, log.DebugLevel, logger.Level())
+	assert.True(t, errors.Is(logger.SetFilter("trace"), log.ErrInvalidMinLevel))
+	assert.True(t, errors.Is(logger.SetFilter("info,db=trace"), log.ErrInvalidMinLevel))
+	assert.Equal(t, log.DebugLevel, logger.Level())
+}
+
+func TestSetHandlerLevel(t *testing.T) {
+	logger := log.New()
+	h1 := memory.New()
+	logger.AddHandler(h1, log.AllLevels...)
+	h2 := memory.New()
+	logger.AddHandler(h2, log.GetLevelsFromMinLevel("error")...)
+
+	level, ok := logger.HandlerLevel(h2)
+	assert.True(t, ok)
+	assert.Equal(t, log.ErrorLevel, level)
+
+	logger.SetHandlerLevel(h1, log.WarnLevel)
+	logger.SetHandlerLevel(h2, log.InfoLevel)
+
+	level, _ = logger.HandlerLevel(h1)
+	assert.Equal(t, log.WarnLevel, level)
+
+	logger.Info("info")
+	assert.Equal(t, "", string(h1.Out))
+	assert.Equal(t, `{"level":"INFO","msg":"info"}`+"\n", string(h2.Out))
+
+	_, ok = logger.HandlerLevel(memory.New())
+	assert.False(t, ok)
+
+	assert.True(t, errors.Is(logger.SetHandlerLevel(h1, log.TraceLevel), log.ErrInvalidMinLevel))
+	level, _ = logger.HandlerLevel(h1)
+	assert.Equal(t, log.WarnLevel, level)
+	assert.True(t, errors.Is(logger.SetHandlerLevel(memory.New(), log.InfoLevel), log.ErrHandlerNotFound))
+}
+
+type flushCounter struct {
+	*memory.Handler
+	flushed int
+}
+
+func (h *flushCounter) Flush() error {
+	h.flushed++
+	return nil
+}
+
+func TestRemoveHandler(t *testing.T) {
+	logger := log.New()
+	h1 := &flushCounter{Handler: memory.New()}
+	logger.AddHandler(h1, log.AllLevels...)
+	h2 := memory.New()
+	err := logger.AddNamedHandler("h2", h2, log.AllLevels...)
+	assert.NoError(t, err)
+
+	err = logger.AddNamedHandler("h2", memory.New(), log.AllLevels...)
+	assert.Error(t, err)
+
+	err = logger.RemoveHandler(h1)
+	assert.NoError(t, err)
+	assert.Equal(t, 1, h1.flushed)
+
+	logger.Info("info")
+	assert.Equal(t, "", string(h1.Out))
+	assert.Equal(t, `{"level":"INFO","msg":"info"}`+"\n", string(h2.Out))
+
+	err = logger.RemoveHandlerByName("h2")
+	assert.NoError(t, err)
+	assert.Len(t, logger.Handlers(), 0)
+
+	assert.Equal(t, log.ErrHandlerNotFound, logger.RemoveHandler(h1))
+	assert.Equal(t, log.ErrHandlerNotFound, logger.RemoveHandlerByName("h2"))
+}
+
+func TestReplaceHandler(t *testing.T) {
+	logger := log.New()
+	h1 := &flushCounter{Handler: memory.New()}
+	err := logger.AddNamedHandler("main", h1, log.GetLevelsFromMinLevel("warn")...)
+	assert.NoError(t, err)
+
+	h2 := &flushCounter{Handler: memory.New()}
+	err = logger.ReplaceHandler(h1, h2)
+	assert.NoError(t, err)
+	assert.Equal(t, 1, h1.flushed)
+
+	logger.Info("info")
+	logger.Warn("warn")
+	assert.Equal(t, "", string(h1.Out))
+	assert.Equal(t, `{"level":"WARN","msg":"warn"}`+"\n", string(h2.Out))
+
+	handlers := logger.Handlers()
+	assert.Len(t, handlers, 1)
+	assert.Equal(t, "main", handlers[0].Name)
+	assert.Equal(t, h2, handlers[0].Handler)
+	assert.Equal(t, log.GetLevelsFromMinLevel("warn"), handlers[0].Levels)
+
+	h3 := memory.New()
+	err = logger.ReplaceHandlerByName("main", h3)
+	assert.NoError(t, err)
+	assert.Equal(t, 1, h2.flushed)
+
+	logger.Error("error")
+	assert.Contains(t, string(h3.Out), `"msg":"error"`)
+
+	assert.Equal(t, log.ErrHandlerNotFound, logger.ReplaceHandler(h1, h3))
+	assert.Equal(t, log.ErrHandlerNotFound, logger.ReplaceHandlerByName("unknown", h3))
+}
+
+func TestReplaceHandlerConcurrently(t *testing.T) {
+	logger := log.New()
+	h := memory.New()
+	logger.AddHandler(h, log.AllLevels...)
+
+	var wg sync.WaitGroup
+	for i := 0; i < 10; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for j := 0; j < 100; j++ {
+				logger.Info("info")
+			}
+		}()
+	}
+
+	for i := 0; i < 10; i++ {
+		next := memory.New()
+		err := logger.ReplaceHandler(h, next)
+		assert.NoError(t, err)
+		h = next
+	}
+	wg.Wait()
+}
+
+func TestRemoveHandlerFromHook(t *testing.T) {
+	logger := log.New()
+	h := &flushCounter{Handler: memory.New()}
+	logger.AddHandler(h, log.AllLevels...)
+	err := logger.AddHandlerHook(h, func(e *log.Entry) error {
+		return logger.RemoveHandler(h)
+	})
+	assert.NoError(t, err)
+
+	logger.Info("first")
+	logger.Info("second")
+
+	err = logger.Close(context.Background())
+	assert.NoError(t, err)
+	assert.Equal(t, 1, h.flushed)
+	assert.Equal(t, `{"level":"INFO","msg":"first"}`+"\n", string(h.Out))
+}
+
+type blockHandler struct {
+	*flushCounter
+	writing chan struct{}
+	release chan struct{}
+}
+
+func (h *blockHandler) Write(bytes []byte) error {
+	close(h.writing)
+	<-h.release
+	return h.flushCounter.Write(bytes)
+}
+
+func TestRemoveBlockedHandler(t *testing.T) {
+	logger := log.New()
+	h := &blockHandler{
+		flushCounter: &flushCounter{Handler: memory.New()},
+		writing:      make(chan struct{}),
+		release:      make(chan struct{}),
+	}
+	logger.AddHandler(h, log.AllLevels...)
+
+	go logger.Info("blocked")
+	<-h.writing
+
+	err := logger.RemoveHandler(h)
+	assert.NoError(t, err)
+
+	close(h.release)
+	err = logger.Close(context.Background())
+	assert.NoError(t, err)
+	assert.Equal(t, 1, h.flushed)
+	assert.Equal(t, `{"level":"INFO","msg":"blocked"}`+"\n", string(h.Out))
+}
+
+type closeHandler struct {
+	*memory.Handler
+	closed  bool
+	err     error
+	release chan struct{}
+}
+
+func (h *closeHandler) Close() error {
+	if h.release != nil {
+		<-h.release
+	}
+	h.closed = true
+	return h.err
+}
+
+func TestClose(t *testing.T) {
+	logger := log.New()
+	h1 := &closeHandler{Handler: memory.New()}
+	logger.AddHandler(h1, log.AllLevels...)
+	oops := errors.New("oops")
+	h2 := &closeHandler{Handler: memory.New(), err: oops}
+	err := logger.AddNamedHandler("h2", h2, log.AllLevels...)
+	assert.NoError(t, err)
+
+	logger.Info("hello")
+	err = logger.Close(context.Background())
+	assert.EqualError(t, err, "log: close handlers: h2: oops")
+	assert.True(t, errors.Is(err, oops))
+	assert.True(t, h1.closed)
+	assert.True(t, h2.closed)
+	assert.Len(t, logger.Handlers(), 0)
+
+	// the memory handler clears its buffer when it is flushed
+	logger.Info("dropped")
+	assert.Equal(t, "", string(h1.Out))
+}
+
+func TestCloseDeadline(t *testing.T) {
+	logger := log.New()
+	h := &closeHandler{Handler: memory.New(), release: make(chan struct{})}
+	defer close(h.release)
+	err := logger.AddNamedHandler("slow", h, log.AllLevels...)
+	assert.NoError(t, err)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+	err = logger.Close(ctx)
+	assert.EqualError(t, err, "log: close handlers: slow: context deadline exceeded")
+	assert.True(t, errors.Is(err, context.DeadlineExceeded))
+}
+
+func TestFatalExit(t *testing.T) {
+	logger := log.New()
+	h := &flushCounter{Handler: memory.New()}
+	logger.AddHandler(h, log.AllLevels...)
+
+	exitCode := -1
+	logger.SetExitFunc(func(code int) {
+		exitCode = code
+	})
+
+	var calls []string
+	logger.AddExitHook(func() {
+		calls = append(calls, "hook")
+	})
+
+	logger.Str("app", "santa").Fatal("boom")
+	assert.Equal(t, 1, exitCode)
+	assert.Equal(t, []string{"hook"}, calls)
+	assert.Equal(t, 1, h.flushed)
+	assert.Contains(t, string(h.Out), `"msg":"boom"`)
+
+	// the exit func returns, so the handlers are still registered
+	h.Out = nil
+	logger.Info("after")
+	assert.Equal(t, `{"level":"INFO","msg":"after"}`+"\n", string(h.Out))
+	assert.Len(t, logger.Handlers(), 1)
+}
+
+func TestPanicFunc(t *testing.T) {
+	logger := log.New()
+	logger.AddHandler(memory.New(), log.AllLevels...)
+
+	var panicErr *log.PanicError
+	logger.SetPanicFunc(func(err *log.PanicError) {
+		panicErr = err
+	})
+	logger.Str("app", "santa").Panicf("boom %d", 1)
+	assert.Equal(t, "boom 1", panicErr.Message)
+	assert.Contains(t, string(panicErr.Fields), `{"app":"santa"`)
+
+	logger.SetPanicFunc(log.PanicWithError)
+	func() {
+		defer func() {
+			err, ok := recover().(*log.PanicError)
+			assert.True(t, ok)
+			assert.EqualError(t, err, "boom")
+			assert.Contains(t, string(err.Fields), `"user_id":1`)
+		}()
+		logger.Int("user_id", 1).Panic("boom")
+	}()
+
+	logger.SetPanicFunc(nil)
+	assert.PanicsWithValue(t, "boom", func() {
+		logger.Panic("boom")
+	})
+}
+
 func TestStdContext(t *testing.T) {
 	log.RemoveAllHandlers()
 
@@ -274,6 +646,28 @@ func TestStandardFields(t *testing.T) {
 
 }
 
+func TestTypedFields(t *testing.T) {
+	logger := log.New()
+	h := memory.New()
+	logger.AddHandler(h, log.AllLevels...)
+
+	c := logger.
+		Bools("bools", []bool{true}).
+		Uints("uints", []uint{1, 2}).
+		Floats64("floats64", []float64{1.5}).
+		Hex("hex", []byte{0xff}).
+		IPAddr("ip", net.ParseIP("::1")).
+		Dur("dur", 3*time.Millisecond).
+		Durs("durs", []time.Duration{time.Second}).
+		Stringer("stringer", time.Second).
+		RawJSON("raw", []byte(`[1,2]`))
+
+	c.Ints("ints", []int{1}).Info("typed")
+
+	//t.Log(string(h.Out))
+	assert.Equal(t, `{"bools":[true],"uints":[1,2],"floats64":[1.5],"hex":"ff","ip":"::1","dur":3,"durs":[1000],"stringer":"1s","raw":[1,2],"ints":[1],"level":"INFO","msg":"typed"}`+"\n", string(h.Out))
+}
+
 func TestAdvancedFields(t *testing.T) {
 	log.RemoveAllHandlers()
 	log.AutoStaceTrace = false
@@ -290,16 +684,18 @@ func TestAdvancedFields(t *testing.T) {
 	log.AutoStaceTrace = true
 }
 
-// func TestTrace(t *testing.T) {
-// 	h := memory.New()
-// 	log.AddHandler(h, log.AllLevels...)
+func TestTrace(t *testing.T) {
+	logger := log.New()
+	h := memory.New()
+	logger.AddHandler(h, log.AllLevels...)
+	logger.SetClock(fixedClock{t: time.Now()})
 
-// 	func() (err error) {
-// 		defer log.Trace("trace").Stop()
-// 		return nil
-// 	}()
-// 	assert.Equal(t, `{"duration":0,"level":"INFO","msg":"trace"}`+"\n", string(h.Out))
-// }
+	func() (err error) {
+		defer logger.Trace("trace").Stop()
+		return nil
+	}()
+	assert.Equal(t, `{"duration":0,"level":"INFO","msg":"trace"}`+"\n", string(h.Out))
+}
 
 type AppHook struct {
 }
@@ -326,6 +722,87 @@ func TestHook(t *testing.T) {
 	assert.Equal(t, `{"app_id":"santa","env":"dev","level":"INFO","msg":"upload complete"}`+"\n", string(h.Out))
 }
 
+func TestHookDropEntry(t *testing.T) {
+	logger := log.New()
+	h := memory.New()
+	logger.AddHandler(h, log.AllLevels...)
+
+	logger.AddHook(func(e *log.Entry) error {
+		if e.Message == "GET /health" {
+			return log.ErrDropEntry
+		}
+		return nil
+	})
+
+	logger.Info("GET /health")
+	logger.Info("GET /users")
+	assert.Equal(t, `{"level":"INFO","msg":"GET /users"}`+"\n", string(h.Out))
+}
+
+func TestHookChangeLevel(t *testing.T) {
+	logger := log.New()
+	h1 := memory.New()
+	logger.AddHandler(h1, log.InfoLevel)
+	h2 := memory.New()
+	logger.AddHandler(h2, log.DebugLevel)
+
+	logger.AddHook(func(e *log.Entry) error {
+		if e.Message == "noisy" {
+			e.Level = log.DebugLevel
+		}
+		return nil
+	})
+
+	logger.Info("noisy")
+	assert.Equal(t, "", string(h1.Out))
+	assert.Equal(t, `{"level":"DEBUG","msg":"noisy"}`+"\n", string(h2.Out))
+}
+
+func TestHandlerHook(t *testing.T) {
+	logger := log.New()
+	h1 := memory.New()
+	logger.AddHandler(h1, log.AllLevels...)
+	h2 := memory.New()
+	logger.AddHandler(h2, log.AllLevels...)
+
+	err := logger.AddHandlerHook(h1, func(e *log.Entry) error {
+		e.Str("app", "santa")
+		return nil
+	})
+	assert.NoError(t, err)
+	err = logger.AddHandlerHook(h2, func(e *log.Entry) error {
+		return log.ErrDropEntry
+	})
+	assert.NoError(t, err)
+
+	logger.Info("hello")
+	assert.Equal(t, `{"app":"santa","level":"INFO","msg":"hello"}`+"\n", string(h1.Out))
+	assert.Equal(t, "", string(h2.Out))
+
+	err = logger.AddHandlerHook(memory.New(), func(e *log.Entry) error { return nil })
+	assert.Equal(t, log.ErrHandlerNotFound, err)
+}
+
+func TestHookError(t *testing.T) {
+	defer func(fn func(err error)) { log.ErrorHandler = fn }(log.ErrorHandler)
+
+	var hookErr error
+	log.ErrorHandler = func(err error) {
+		hookErr = err
+	}
+
+	logger := log.New()
+	h := memory.New()
+	logger.AddHandler(h, log.AllLevels...)
+	logger.AddHook(func(e *log.Entry) error {
+		return errors.New("oops")
+	})
+
+	logger.Info("hello")
+	assert.EqualError(t, hookErr, "oops")
+	assert.Equal(t, `{"level":"INFO","msg":"hello"}`+"\n", string(h.Out))
+}
+
 func TestGoroutineSafe(t *testing.T) {
 	log.RemoveAllHandlers()
 
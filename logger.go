@@ -0,0 +1,896 @@
+package log
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// ErrHandlerNotFound is returned when the handler isn't registered on the logger
+var ErrHandlerNotFound = errors.New("log: handler not found")
+
+// Logger is an independent logger instance. Each logger owns its handlers,
+// hooks and default fields, so several loggers can be used in one process.
+type Logger struct {
+	handles         []namedHandler
+	hooks           []Hookfunc
+	exitHooks       []func()
+	leveledHandlers map[Level][]Handler
+	handlerSet      atomic.Value    // *handlerSet
+	retiredSets     []*handlerSet   // replaced snapshots which may still be in use
+	flushes         []chan struct{} // closed when a removed handler has been flushed in the background
+	level           uint32
+	nameLevels      atomic.Value // map[string]Level
+	sampler         atomic.Value // *Sampler
+	caller          atomic.Value // CallerConfig
+	timestamp       atomic.Value // TimestampConfig
+	clock           atomic.Value // clockHolder
+	fieldNames      atomic.Value // FieldNames
+	exitFunc        atomic.Value // func(code int)
+	panicFunc       atomic.Value // PanicFunc
+	recovery        atomic.Value // RecoverConfig
+	extractors      atomic.Value // []ContextExtractor
+	tracing         atomic.Value // TracingConfig
+	errors          atomic.Value // ErrorConfig
+	stack           atomic.Value // StackConfig
+	rwMutex         sync.RWMutex
+	buf             []byte
+}
+
+type namedHandler struct {
+	name    string
+	handler Handler
+	hooks   []Hookfunc
+}
+
+// handlerSet is a snapshot of handlers and hooks, so entries can be written without any lock
+type handlerSet struct {
+	cacheLeveledHandlers func(level Level) []Handler
+	hooks                []Hookfunc
+	handlerHooks         map[Handler][]Hookfunc
+	active               int64 // the number of entries which are being written
+	retired              int32 // set when the snapshot has been replaced
+	done                 chan struct{}
+	doneOnce             sync.Once
+}
+
+func newHandlerSet(cacheLeveledHandlers func(level Level) []Handler, hooks []Hookfunc, handlerHooks map[Handler][]Hookfunc) *handlerSet {
+	return &handlerSet{
+		cacheLeveledHandlers: cacheLeveledHandlers,
+		hooks:                hooks,
+		handlerHooks:         handlerHooks,
+		done:                 make(chan struct{}),
+	}
+}
+
+// New creates a new Logger instance without any handler
+func New() *Logger {
+	logger := Logger{
+		leveledHandlers: map[Level][]Handler{},
+	}
+
+	logger.handlerSet.Store(newHandlerSet(logger.getLeveledHandlers(), nil, nil))
+	logger.nameLevels.Store(map[string]Level{})
+	logger.sampler.Store((*Sampler)(nil))
+	logger.caller.Store(CallerConfig{})
+	logger.timestamp.Store(TimestampConfig{})
+	logger.SetClock(nil)
+	logger.SetExitFunc(nil)
+	logger.SetPanicFunc(nil)
+	logger.recovery.Store(RecoverConfig{})
+	logger.extractors.Store([]ContextExtractor{})
+	logger.SetTracing(TracingConfig{})
+	logger.errors.Store(ErrorConfig{})
+	logger.SetStackTrace(StackConfig{})
+	logger.fieldNames.Store(DefaultFieldNames())
+	return &logger
+}
+
+func (l *Logger) getLeveledHandlers() func(level Level) []Handler {
+	debugHandlers := l.leveledHandlers[DebugLevel]
+	infoHandlers := l.leveledHandlers[InfoLevel]
+	warnHandlers := l.leveledHandlers[WarnLevel]
+	errorHandlers := l.leveledHandlers[ErrorLevel]
+	panicHandlers := l.leveledHandlers[PanicLevel]
+	fatalHandlers := l.leveledHandlers[FatalLevel]
+
+	return func(level Level) []Handler {
+		switch level {
+		case DebugLevel:
+			return debugHandlers
+		case InfoLevel:
+			return infoHandlers
+		case WarnLevel:
+			return warnHandlers
+		case ErrorLevel:
+			return errorHandlers
+		case PanicLevel:
+			return panicHandlers
+		case FatalLevel:
+			return fatalHandlers
+		}
+
+		return []Handler{}
+	}
+}
+
+// newEntry creates an entry with the default fields of the logger.  callerSkip is the number
+// of frames between the caller and the entry's method.
+func (l *Logger) newEntry(callerSkip int) *Entry {
+	e := newEntry(l, l.buf)
+	e.callerSkip = callerSkip
+	return e
+}
+
+// updateHandlerSet replaces the snapshot of handlers and hooks and returns the replaced
+// snapshots which may still be in use.  The caller must hold the lock.
+func (l *Logger) updateHandlerSet() handlerSets {
+	old := l.handlerSet.Load().(*handlerSet)
+	var handlerHooks map[Handler][]Hookfunc
+	for _, h := range l.handles {
+		if len(h.hooks) > 0 {
+			if handlerHooks == nil {
+				handlerHooks = map[Handler][]Hookfunc{}
+			}
+			handlerHooks[h.handler] = h.hooks
+		}
+	}
+
+	l.handlerSet.Store(newHandlerSet(l.getLeveledHandlers(), l.hooks, handlerHooks))
+
+	// a replaced snapshot can't be acquired again, so it is done once nobody uses it
+	atomic.StoreInt32(&old.retired, 1)
+	if atomic.LoadInt64(&old.active) == 0 {
+		old.finish()
+	}
+	retired := make([]*handlerSet, 0, len(l.retiredSets)+1)
+	for _, set := range append(l.retiredSets, old) {
+		if !set.finished() {
+			retired = append(retired, set)
+		}
+	}
+	l.retiredSets = retired
+	return retired
+}
+
+// acquireHandlerSet returns the current snapshot of handlers and hooks.  The snapshot
+// must be released after the entry is written.
+func (l *Logger) acquireHandlerSet() *handlerSet {
+	for {
+		set := l.handlerSet.Load().(*handlerSet)
+		atomic.AddInt64(&set.active, 1)
+		if l.handlerSet.Load().(*handlerSet) == set {
+			return set
+		}
+		// the snapshot was replaced before we marked it active, so it may be waited already
+		set.release()
+	}
+}
+
+func (s *handlerSet) release() {
+	if atomic.AddInt64(&s.active, -1) == 0 && atomic.LoadInt32(&s.retired) == 1 {
+		s.finish()
+	}
+}
+
+// finish closes the done channel of a retired snapshot which nobody uses
+func (s *handlerSet) finish() {
+	s.doneOnce.Do(func() { close(s.done) })
+}
+
+func (s *handlerSet) finished() bool {
+	select {
+	case <-s.done:
+		return true
+	default:
+		return false
+	}
+}
+
+type handlerSets []*handlerSet
+
+// wait blocks until all entries which use the snapshots have been written
+func (sets handlerSets) wait() {
+	for _, s := range sets {
+		<-s.done
+	}
+}
+
+// flushRemoved flushes a handler which has been removed or replaced.  When no entry is being
+// written, the handler is flushed at once and the error is returned.  Otherwise the handler is
+// flushed in the background after the entries have been written, and the error is reported by
+// ReportError.  So a hook or a handler can remove handlers without waiting for its own entry,
+// and a stuck handler doesn't block the caller.  Close waits for the background flushes.
+// The caller must hold the lock, and the lock is released before flushing.
+func (l *Logger) flushRemoved(retired handlerSets, handler Handler) error {
+	busy := retired[:0:0]
+	for _, s := range retired {
+		if !s.finished() {
+			busy = append(busy, s)
+		}
+	}
+	if len(busy) == 0 {
+		l.rwMutex.Unlock()
+		return flushHandler(handler)
+	}
+
+	flushed := make(chan struct{})
+	flushes := make([]chan struct{}, 0, len(l.flushes)+1)
+	for _, f := range l.flushes {
+		select {
+		case <-f:
+		default:
+			flushes = append(flushes, f)
+		}
+	}
+	l.flushes = append(flushes, flushed)
+	l.rwMutex.Unlock()
+
+	go func() {
+		defer close(flushed)
+		busy.wait()
+		if err := flushHandler(handler); err != nil {
+			ReportError("log: flush removed log handler: %v", err)
+		}
+	}()
+	return nil
+}
+
+// SetLevel changes the minimum level of the logger at runtime. Entries below
+// the level are dropped before they reach any handler.  TraceLevel and unknown
+// levels are rejected with ErrInvalidMinLevel.
+func (l *Logger) SetLevel(level Level) error {
+	if err := validMinLevel(level); err != nil {
+		return err
+	}
+	atomic.StoreUint32(&l.level, uint32(level))
+	return nil
+}
+
+// Level returns the minimum level of the logger
+func (l *Logger) Level() Level {
+	return Level(atomic.LoadUint32(&l.level))
+}
+
+// Enabled reports whether the logger writes entries of the level
+func (l *Logger) Enabled(level Level) bool {
+	return level >= l.Level()
+}
+
+// SetSampler sets the sampler which limits the entries with the same level and message.
+// A nil sampler disables sampling.
+func (l *Logger) SetSampler(sampler *Sampler) {
+	l.sampler.Store(sampler)
+}
+
+// sample reports whether the entry passes the sampler of the logger
+func (l *Logger) sample(e *Entry) bool {
+	sampler := l.sampler.Load().(*Sampler)
+	if sampler == nil {
+		return true
+	}
+	return sampler.Sample(e.Level, e.Message)
+}
+
+// SetHandlerLevel changes the minimum level of a registered handler at runtime.  TraceLevel
+// and unknown levels are rejected with ErrInvalidMinLevel.
+func (l *Logger) SetHandlerLevel(handler Handler, level Level) error {
+	if err := validMinLevel(level); err != nil {
+		return err
+	}
+
+	l.rwMutex.Lock()
+	defer l.rwMutex.Unlock()
+
+	if l.indexOfHandler(handler) < 0 {
+		return ErrHandlerNotFound
+	}
+
+	for lvl, handlers := range l.leveledHandlers {
+		l.leveledHandlers[lvl] = removeHandler(handlers, handler)
+	}
+
+	for _, lvl := range GetLevelsFromMinLevel(level.String()) {
+		l.leveledHandlers[lvl] = append(l.leveledHandlers[lvl], handler)
+	}
+
+	l.updateHandlerSet()
+	return nil
+}
+
+// HandlerLevel returns the minimum level of a registered handler.  The second
+// return value is false if the handler isn't registered for any level.
+func (l *Logger) HandlerLevel(handler Handler) (Level, bool) {
+	l.rwMutex.RLock()
+	defer l.rwMutex.RUnlock()
+
+	for _, lvl := range AllLevels {
+		if containsHandler(l.leveledHandlers[lvl], handler) {
+			return lvl, true
+		}
+	}
+	return DebugLevel, false
+}
+
+func containsHandler(handlers []Handler, handler Handler) bool {
+	for _, h := range handlers {
+		if h == handler {
+			return true
+		}
+	}
+	return false
+}
+
+// removeHandler returns a new slice without the handler, so snapshots which
+// hold the old slice aren't affected.
+func removeHandler(handlers []Handler, handler Handler) []Handler {
+	result := make([]Handler, 0, len(handlers))
+	for _, h := range handlers {
+		if h != handler {
+			result = append(result, h)
+		}
+	}
+	return result
+}
+
+// AddHandler adds a new Log Handler and specifies what log levels
+// the handler will be passed log entries for
+func (l *Logger) AddHandler(handler Handler, levels ...Level) {
+	l.rwMutex.Lock()
+	defer l.rwMutex.Unlock()
+
+	for _, level := range levels {
+		l.leveledHandlers[level] = append(l.leveledHandlers[level], handler)
+	}
+
+	l.handles = append(l.handles, namedHandler{handler: handler})
+	l.updateHandlerSet()
+}
+
+// RemoveAllHandlers removes all handlers
+func (l *Logger) RemoveAllHandlers() {
+	l.rwMutex.Lock()
+	defer l.rwMutex.Unlock()
+
+	l.leveledHandlers = map[Level][]Handler{}
+	l.handles = []namedHandler{}
+	l.hooks = []Hookfunc{}
+	l.updateHandlerSet()
+}
+
+// AddNamedHandler adds a new Log Handler with a name, so it can be removed or replaced by the name later
+func (l *Logger) AddNamedHandler(name string, handler Handler, levels ...Level) error {
+	l.rwMutex.Lock()
+	defer l.rwMutex.Unlock()
+
+	if len(name) > 0 && l.indexOfName(name) >= 0 {
+		return fmt.Errorf("log: handler %q already exists", name)
+	}
+
+	for _, level := range levels {
+		l.leveledHandlers[level] = append(l.leveledHandlers[level], handler)
+	}
+
+	l.handles = append(l.handles, namedHandler{name: name, handler: handler})
+	l.updateHandlerSet()
+	return nil
+}
+
+// RemoveHandler removes the handler, and the handler is flushed after the entries
+// which are being written to it have been done.  It doesn't wait for those entries:
+// while any is being written, the handler is flushed in the background and the error
+// is reported by ReportError, so it is safe to call from a hook or a handler.
+func (l *Logger) RemoveHandler(handler Handler) error {
+	l.rwMutex.Lock()
+	idx := l.indexOfHandler(handler)
+	if idx < 0 {
+		l.rwMutex.Unlock()
+		return ErrHandlerNotFound
+	}
+	return l.removeHandlerAt(idx)
+}
+
+// RemoveHandlerByName removes the handler of the name, and the handler is flushed after
+// the entries which are being written to it have been done.
+func (l *Logger) RemoveHandlerByName(name string) error {
+	l.rwMutex.Lock()
+	idx := l.indexOfName(name)
+	if idx < 0 {
+		l.rwMutex.Unlock()
+		return ErrHandlerNotFound
+	}
+	return l.removeHandlerAt(idx)
+}
+
+// removeHandlerAt removes the handler of the index.  The caller must hold the lock, and
+// the lock is released before flushing.
+func (l *Logger) removeHandlerAt(idx int) error {
+	handler := l.handles[idx].handler
+
+	handles := make([]namedHandler, 0, len(l.handles)-1)
+	handles = append(handles, l.handles[:idx]...)
+	l.handles = append(handles, l.handles[idx+1:]...)
+
+	for lvl, handlers := range l.leveledHandlers {
+		l.leveledHandlers[lvl] = removeHandler(handlers, handler)
+	}
+
+	return l.flushRemoved(l.updateHandlerSet(), handler)
+}
+
+// ReplaceHandler atomically swaps the old handler with the new handler.  The new handler
+// takes over the name and levels of the old one, and the old handler is flushed after the
+// entries which are being written to it have been done, as RemoveHandler does.
+func (l *Logger) ReplaceHandler(oldHandler Handler, newHandler Handler) error {
+	l.rwMutex.Lock()
+	idx := l.indexOfHandler(oldHandler)
+	if idx < 0 {
+		l.rwMutex.Unlock()
+		return ErrHandlerNotFound
+	}
+	return l.replaceHandlerAt(idx, newHandler)
+}
+
+// ReplaceHandlerByName atomically swaps the handler of the name with the new handler.
+// The old handler is flushed after the entries which are being written to it have been done.
+func (l *Logger) ReplaceHandlerByName(name string, handler Handler) error {
+	l.rwMutex.Lock()
+	idx := l.indexOfName(name)
+	if idx < 0 {
+		l.rwMutex.Unlock()
+		return ErrHandlerNotFound
+	}
+	return l.replaceHandlerAt(idx, handler)
+}
+
+// replaceHandlerAt replaces the handler of the index.  The caller must hold the lock, and
+// the lock is released before flushing.
+func (l *Logger) replaceHandlerAt(idx int, handler Handler) error {
+	oldHandler := l.handles[idx].handler
+
+	handles := make([]namedHandler, len(l.handles))
+	copy(handles, l.handles)
+	handles[idx].handler = handler
+	l.handles = handles
+
+	for lvl, handlers := range l.leveledHandlers {
+		newHandlers := make([]Handler, len(handlers))
+		for i, h := range handlers {
+			if h == oldHandler {
+				h = handler
+			}
+			newHandlers[i] = h
+		}
+		l.leveledHandlers[lvl] = newHandlers
+	}
+
+	return l.flushRemoved(l.updateHandlerSet(), oldHandler)
+}
+
+// HandlerInfo describes a registered handler
+type HandlerInfo struct {
+	Name    string
+	Handler Handler
+	Levels  []Level
+}
+
+// Handlers returns the registered handlers and their levels
+func (l *Logger) Handlers() []HandlerInfo {
+	l.rwMutex.RLock()
+	defer l.rwMutex.RUnlock()
+
+	infos := make([]HandlerInfo, 0, len(l.handles))
+	for _, h := range l.handles {
+		info := HandlerInfo{
+			Name:    h.name,
+			Handler: h.handler,
+			Levels:  []Level{},
+		}
+		for _, lvl := range AllLevels {
+			if containsHandler(l.leveledHandlers[lvl], h.handler) {
+				info.Levels = append(info.Levels, lvl)
+			}
+		}
+		infos = append(infos, info)
+	}
+	return infos
+}
+
+func (l *Logger) indexOfHandler(handler Handler) int {
+	for i, h := range l.handles {
+		if h.handler == handler {
+			return i
+		}
+	}
+	return -1
+}
+
+func (l *Logger) indexOfName(name string) int {
+	for i, h := range l.handles {
+		if len(h.name) > 0 && h.name == name {
+			return i
+		}
+	}
+	return -1
+}
+
+// AddHook adds a new Hook to log entry
+func (l *Logger) AddHook(hook Hookfunc) error {
+	l.rwMutex.Lock()
+	defer l.rwMutex.Unlock()
+
+	l.hooks = append(l.hooks, hook)
+	l.updateHandlerSet()
+	return nil
+}
+
+// AddHandlerHook adds a new Hook which only runs for the handler.  The hooks of the logger
+// run before the hooks of the handler.
+func (l *Logger) AddHandlerHook(handler Handler, hook Hookfunc) error {
+	l.rwMutex.Lock()
+	defer l.rwMutex.Unlock()
+
+	idx := l.indexOfHandler(handler)
+	if idx < 0 {
+		return ErrHandlerNotFound
+	}
+
+	handles := make([]namedHandler, len(l.handles))
+	copy(handles, l.handles)
+	hooks := make([]Hookfunc, 0, len(handles[idx].hooks)+1)
+	handles[idx].hooks = append(append(hooks, handles[idx].hooks...), hook)
+	l.handles = handles
+
+	l.updateHandlerSet()
+	return nil
+}
+
+// Flush clear all handler's buffer
+func (l *Logger) Flush() {
+	l.rwMutex.RLock()
+	handles := l.handles
+	l.rwMutex.RUnlock()
+
+	for _, h := range handles {
+		err := flushHandler(h.handler)
+		if err != nil {
+			printError("log: flush log handler: %v", err)
+		}
+	}
+}
+
+func flushHandler(h Handler) error {
+	flusher, ok := h.(Flusher)
+	if ok {
+		return flusher.Flush()
+	}
+	return nil
+}
+
+// Debug level formatted message
+func (l *Logger) Debug(msg string) {
+	l.newEntry(1).Debug(msg)
+}
+
+// Debugf level formatted message
+func (l *Logger) Debugf(msg string, v ...interface{}) {
+	l.newEntry(1).Debugf(msg, v...)
+}
+
+// Info level formatted message
+func (l *Logger) Info(msg string) {
+	l.newEntry(1).Info(msg)
+}
+
+// Infof level formatted message
+func (l *Logger) Infof(msg string, v ...interface{}) {
+	l.newEntry(1).Infof(msg, v...)
+}
+
+// Warn level formatted message
+func (l *Logger) Warn(msg string) {
+	l.newEntry(1).Warn(msg)
+}
+
+// Warnf level formatted message
+func (l *Logger) Warnf(msg string, v ...interface{}) {
+	l.newEntry(1).Warnf(msg, v...)
+}
+
+// Error level formatted message
+func (l *Logger) Error(msg string) {
+	l.newEntry(1).Error(msg)
+}
+
+// Errorf level formatted message
+func (l *Logger) Errorf(msg string, v ...interface{}) {
+	l.newEntry(1).Errorf(msg, v...)
+}
+
+// Panic level formatted message
+func (l *Logger) Panic(msg string) {
+	l.newEntry(1).Panic(msg)
+}
+
+// Panicf level formatted message
+func (l *Logger) Panicf(msg string, v ...interface{}) {
+	l.newEntry(1).Panicf(msg, v...)
+}
+
+// Fatal level formatted message, followed by an exit.
+func (l *Logger) Fatal(msg string) {
+	l.newEntry(1).Fatal(msg)
+}
+
+// Fatalf level formatted message, followed by an exit.
+func (l *Logger) Fatalf(msg string, v ...interface{}) {
+	l.newEntry(1).Fatalf(msg, v...)
+}
+
+// Trace returns a new entry with a Stop method to fire off
+// a corresponding completion log, useful with defer.
+func (l *Logger) Trace(msg string) *Entry {
+	e := newEntry(l, nil)
+	return e.Trace(msg)
+}
+
+// Str add string field to current context
+func (l *Logger) Str(key string, val string) Context {
+	c := newContext(l)
+	return c.Str(key, val)
+}
+
+// Bool add bool field to current context
+func (l *Logger) Bool(key string, val bool) Context {
+	c := newContext(l)
+	return c.Bool(key, val)
+}
+
+// Int add Int field to current context
+func (l *Logger) Int(key string, val int) Context {
+	c := newContext(l)
+	return c.Int(key, val)
+}
+
+// Int8 add Int8 field to current context
+func (l *Logger) Int8(key string, val int8) Context {
+	c := newContext(l)
+	return c.Int8(key, val)
+}
+
+// Int16 add Int16 field to current context
+func (l *Logger) Int16(key string, val int16) Context {
+	c := newContext(l)
+	return c.Int16(key, val)
+}
+
+// Int32 add Int32 field to current context
+func (l *Logger) Int32(key string, val int32) Context {
+	c := newContext(l)
+	return c.Int32(key, val)
+}
+
+// Int64 add Int64 field to current context
+func (l *Logger) Int64(key string, val int64) Context {
+	c := newContext(l)
+	return c.Int64(key, val)
+}
+
+// Uint add Uint field to current context
+func (l *Logger) Uint(key string, val uint) Context {
+	c := newContext(l)
+	return c.Uint(key, val)
+}
+
+// Uint8 add Uint8 field to current context
+func (l *Logger) Uint8(key string, val uint8) Context {
+	c := newContext(l)
+	return c.Uint8(key, val)
+}
+
+// Uint16 add Uint16 field to current context
+func (l *Logger) Uint16(key string, val uint16) Context {
+	c := newContext(l)
+	return c.Uint16(key, val)
+}
+
+// Uint32 add Uint32 field to current context
+func (l *Logger) Uint32(key string, val uint32) Context {
+	c := newContext(l)
+	return c.Uint32(key, val)
+}
+
+// Uint64 add Uint64 field to current context
+func (l *Logger) Uint64(key string, val uint64) Context {
+	c := newContext(l)
+	return c.Uint64(key, val)
+}
+
+// Float32 add float32 field to current context
+func (l *Logger) Float32(key string, val float32) Context {
+	c := newContext(l)
+	return c.Float32(key, val)
+}
+
+// Float64 add Float64 field to current context
+func (l *Logger) Float64(key string, val float64) Context {
+	c := newContext(l)
+	return c.Float64(key, val)
+}
+
+// Strs add string array field to current context
+func (l *Logger) Strs(key string, val []string) Context {
+	c := newContext(l)
+	return c.Strs(key, val)
+}
+
+// Ints add Int array field to current context
+func (l *Logger) Ints(key string, val []int) Context {
+	c := newContext(l)
+	return c.Ints(key, val)
+}
+
+// Dur adds Duration field to current context
+func (l *Logger) Dur(key string, val time.Duration) Context {
+	c := newContext(l)
+	return c.Dur(key, val)
+}
+
+// Time adds Time field to current context
+func (l *Logger) Time(key string, val time.Time) Context {
+	c := newContext(l)
+	return c.Time(key, val)
+}
+
+// Times adds Time array field to current context
+func (l *Logger) Times(key string, val []time.Time) Context {
+	c := newContext(l)
+	return c.Times(key, val)
+}
+
+// Interface adds the field key with i marshaled using reflection.
+func (l *Logger) Interface(key string, val interface{}) Context {
+	c := newContext(l)
+	return c.Interface(key, val)
+}
+
+// Stringer adds the string of the Stringer field to current context, or null if it is nil
+func (l *Logger) Stringer(key string, val fmt.Stringer) Context {
+	c := newContext(l)
+	return c.Stringer(key, val)
+}
+
+// Bools add bool array field to current context
+func (l *Logger) Bools(key string, val []bool) Context {
+	c := newContext(l)
+	return c.Bools(key, val)
+}
+
+// Ints8 add Int8 array field to current context
+func (l *Logger) Ints8(key string, val []int8) Context {
+	c := newContext(l)
+	return c.Ints8(key, val)
+}
+
+// Ints16 add Int16 array field to current context
+func (l *Logger) Ints16(key string, val []int16) Context {
+	c := newContext(l)
+	return c.Ints16(key, val)
+}
+
+// Ints32 add Int32 array field to current context
+func (l *Logger) Ints32(key string, val []int32) Context {
+	c := newContext(l)
+	return c.Ints32(key, val)
+}
+
+// Ints64 add Int64 array field to current context
+func (l *Logger) Ints64(key string, val []int64) Context {
+	c := newContext(l)
+	return c.Ints64(key, val)
+}
+
+// Uints add Uint array field to current context
+func (l *Logger) Uints(key string, val []uint) Context {
+	c := newContext(l)
+	return c.Uints(key, val)
+}
+
+// Uints8 add Uint8 array field to current context
+func (l *Logger) Uints8(key string, val []uint8) Context {
+	c := newContext(l)
+	return c.Uints8(key, val)
+}
+
+// Uints16 add Uint16 array field to current context
+func (l *Logger) Uints16(key string, val []uint16) Context {
+	c := newContext(l)
+	return c.Uints16(key, val)
+}
+
+// Uints32 add Uint32 array field to current context
+func (l *Logger) Uints32(key string, val []uint32) Context {
+	c := newContext(l)
+	return c.Uints32(key, val)
+}
+
+// Uints64 add Uint64 array field to current context
+func (l *Logger) Uints64(key string, val []uint64) Context {
+	c := newContext(l)
+	return c.Uints64(key, val)
+}
+
+// Floats32 add Float32 array field to current context
+func (l *Logger) Floats32(key string, val []float32) Context {
+	c := newContext(l)
+	return c.Floats32(key, val)
+}
+
+// Floats64 add Float64 array field to current context
+func (l *Logger) Floats64(key string, val []float64) Context {
+	c := newContext(l)
+	return c.Floats64(key, val)
+}
+
+// Bytes add bytes field as a string to current context
+func (l *Logger) Bytes(key string, val []byte) Context {
+	c := newContext(l)
+	return c.Bytes(key, val)
+}
+
+// Hex add bytes field as a hex string to current context
+func (l *Logger) Hex(key string, val []byte) Context {
+	c := newContext(l)
+	return c.Hex(key, val)
+}
+
+// Durs add Duration array field in milliseconds to current context
+func (l *Logger) Durs(key string, val []time.Duration) Context {
+	c := newContext(l)
+	return c.Durs(key, val)
+}
+
+// IPAddr add IPv4 or IPv6 address field to current context
+func (l *Logger) IPAddr(key string, val net.IP) Context {
+	c := newContext(l)
+	return c.IPAddr(key, val)
+}
+
+// IPPrefix add IPv4 or IPv6 prefix (address and mask) field to current context
+func (l *Logger) IPPrefix(key string, val net.IPNet) Context {
+	c := newContext(l)
+	return c.IPPrefix(key, val)
+}
+
+// MACAddr add MAC address field to current context
+func (l *Logger) MACAddr(key string, val net.HardwareAddr) Context {
+	c := newContext(l)
+	return c.MACAddr(key, val)
+}
+
+// RawJSON add already encoded JSON field to current context.  The value must be valid JSON
+func (l *Logger) RawJSON(key string, val []byte) Context {
+	c := newContext(l)
+	return c.RawJSON(key, val)
+}
+
+// Err add error field to current context
+func (l *Logger) Err(err error) Context {
+	c := newContext(l)
+	return c.Err(err)
+}
+
+// FromContext return a log context from the standard context.  If the
+// standard context doesn't carry a log context, a blank context of the logger is returned.
+func (l *Logger) FromContext(ctx context.Context) Context {
+	v := ctx.Value(ctxKey)
+	if v == nil {
+		return newContext(l)
+	}
+
+	return v.(Context)
+}
@@ -0,0 +1,242 @@
+package log
+
+import (
+	"reflect"
+	"sync"
+	"time"
+)
+
+// LogObjectMarshaler is implemented by types which write their fields into a JSON object
+// through the typed encoder, so Object doesn't use reflection.
+type LogObjectMarshaler interface {
+	MarshalLogObject(d *Dict)
+}
+
+// LogArrayMarshaler is implemented by types which write their elements into a JSON array
+// through the typed encoder, so Array doesn't use reflection.
+type LogArrayMarshaler interface {
+	MarshalLogArray(a *Array)
+}
+
+var arrayPool = &sync.Pool{
+	New: func() interface{} {
+		return &Array{}
+	},
+}
+
+// Array builds a JSON array.  It writes into the buffer of the entry or context
+// directly, so it must not be used after MarshalLogArray returns.
+type Array struct {
+	buf []byte
+}
+
+// isNilPointer reports whether v is nil or a typed nil pointer, whose marshal method would panic
+// unless it handles the nil receiver
+func isNilPointer(v interface{}) bool {
+	if v == nil {
+		return true
+	}
+	rv := reflect.ValueOf(v)
+	return rv.Kind() == reflect.Ptr && rv.IsNil()
+}
+
+// appendObject appends the object to buf.  A nil object, including a typed nil pointer, is written as null.
+func appendObject(buf []byte, obj LogObjectMarshaler) []byte {
+	if isNilPointer(obj) {
+		return enc.AppendNil(buf)
+	}
+
+	d := dictPool.Get().(*Dict)
+	d.buf = enc.AppendBeginMarker(buf)
+	obj.MarshalLogObject(d)
+	buf = enc.AppendEndMarker(d.buf)
+	d.buf = nil
+	dictPool.Put(d)
+	return buf
+}
+
+// appendArray appends the array to buf.  A nil array, including a typed nil pointer, is written as null.
+func appendArray(buf []byte, arr LogArrayMarshaler) []byte {
+	if isNilPointer(arr) {
+		return enc.AppendNil(buf)
+	}
+
+	a := arrayPool.Get().(*Array)
+	a.buf = enc.AppendArrayStart(buf)
+	arr.MarshalLogArray(a)
+	buf = enc.AppendArrayEnd(a.buf)
+	a.buf = nil
+	arrayPool.Put(a)
+	return buf
+}
+
+// Object adds the field key with the object which is written by MarshalLogObject
+func (e *Entry) Object(key string, obj LogObjectMarshaler) *Entry {
+	if e == nil {
+		return e
+	}
+	e.buf = enc.AppendKey(e.buf, key)
+	e.buf = appendObject(e.buf, obj)
+	return e
+}
+
+// Array adds the field key with the array which is written by MarshalLogArray
+func (e *Entry) Array(key string, arr LogArrayMarshaler) *Entry {
+	if e == nil {
+		return e
+	}
+	e.buf = enc.AppendKey(e.buf, key)
+	e.buf = appendArray(e.buf, arr)
+	return e
+}
+
+// Object adds the field key with the object which is written by MarshalLogObject
+func (c Context) Object(key string, obj LogObjectMarshaler) Context {
+	c.buf = copyBytes(c.buf)
+	c.buf = enc.AppendKey(c.buf, key)
+	c.buf = appendObject(c.buf, obj)
+	return c
+}
+
+// Array adds the field key with the array which is written by MarshalLogArray
+func (c Context) Array(key string, arr LogArrayMarshaler) Context {
+	c.buf = copyBytes(c.buf)
+	c.buf = enc.AppendKey(c.buf, key)
+	c.buf = appendArray(c.buf, arr)
+	return c
+}
+
+// Object adds the field key with the object which is written by MarshalLogObject
+func (l *Logger) Object(key string, obj LogObjectMarshaler) Context {
+	c := newContext(l)
+	return c.Object(key, obj)
+}
+
+// Array adds the field key with the array which is written by MarshalLogArray
+func (l *Logger) Array(key string, arr LogArrayMarshaler) Context {
+	c := newContext(l)
+	return c.Array(key, arr)
+}
+
+// Object adds the field key with the object which is written by MarshalLogObject.  There is no
+// package-level Array because the name is taken by the type, use Default().Array instead.
+func Object(key string, obj LogObjectMarshaler) Context {
+	return _logger.Object(key, obj)
+}
+
+// Object adds the field key with the object which is written by MarshalLogObject
+func (d *Dict) Object(key string, obj LogObjectMarshaler) *Dict {
+	d.buf = enc.AppendKey(d.buf, key)
+	d.buf = appendObject(d.buf, obj)
+	return d
+}
+
+// Array adds the field key with the array which is written by MarshalLogArray
+func (d *Dict) Array(key string, arr LogArrayMarshaler) *Dict {
+	d.buf = enc.AppendKey(d.buf, key)
+	d.buf = appendArray(d.buf, arr)
+	return d
+}
+
+// delim separates the elements
+func (a *Array) delim() {
+	if a.buf[len(a.buf)-1] != '[' {
+		a.buf = enc.AppendArrayDelim(a.buf)
+	}
+}
+
+// Object appends the object which is written by MarshalLogObject
+func (a *Array) Object(obj LogObjectMarshaler) *Array {
+	a.delim()
+	a.buf = appendObject(a.buf, obj)
+	return a
+}
+
+// Array appends the nested array which is written by MarshalLogArray
+func (a *Array) Array(arr LogArrayMarshaler) *Array {
+	a.delim()
+	a.buf = appendArray(a.buf, arr)
+	return a
+}
+
+// Dict appends the object which is built by fn
+func (a *Array) Dict(fn func(d *Dict)) *Array {
+	a.delim()
+	d := dictPool.Get().(*Dict)
+	d.buf = enc.AppendBeginMarker(a.buf)
+	fn(d)
+	a.buf = enc.AppendEndMarker(d.buf)
+	d.buf = nil
+	dictPool.Put(d)
+	return a
+}
+
+// Str appends string element
+func (a *Array) Str(val string) *Array {
+	a.delim()
+	a.buf = enc.AppendString(a.buf, val)
+	return a
+}
+
+// Bool appends bool element
+func (a *Array) Bool(val bool) *Array {
+	a.delim()
+	a.buf = enc.AppendBool(a.buf, val)
+	return a
+}
+
+// Int appends Int element
+func (a *Array) Int(val int) *Array {
+	a.delim()
+	a.buf = enc.AppendInt(a.buf, val)
+	return a
+}
+
+// Int64 appends Int64 element
+func (a *Array) Int64(val int64) *Array {
+	a.delim()
+	a.buf = enc.AppendInt64(a.buf, val)
+	return a
+}
+
+// Uint appends Uint element
+func (a *Array) Uint(val uint) *Array {
+	a.delim()
+	a.buf = enc.AppendUint(a.buf, val)
+	return a
+}
+
+// Uint64 appends Uint64 element
+func (a *Array) Uint64(val uint64) *Array {
+	a.delim()
+	a.buf = enc.AppendUint64(a.buf, val)
+	return a
+}
+
+// Float64 appends Float64 element
+func (a *Array) Float64(val float64) *Array {
+	a.delim()
+	a.buf = enc.AppendFloat64(a.buf, val)
+	return a
+}
+
+// Time appends Time element
+func (a *Array) Time(val time.Time) *Array {
+	a.delim()
+	a.buf = enc.AppendTime(a.buf, val, time.RFC3339)
+	return a
+}
+
+// Dur appends Duration element in milliseconds
+func (a *Array) Dur(val time.Duration) *Array {
+	a.delim()
+	a.buf = enc.AppendDuration(a.buf, val, time.Millisecond, false)
+	return a
+}
+
+// Interface appends the element marshaled using reflection.
+func (a *Array) Interface(val interface{}) *Array {
+	a.delim()
+	a.buf = enc.AppendInterface(a.buf, val)
+	return a
+}
@@ -0,0 +1,97 @@
+package log_test
+
+import (
+	"testing"
+
+	"github.com/jasonsoft/log/v2"
+	"github.com/jasonsoft/log/v2/handlers/memory"
+	"github.com/stretchr/testify/assert"
+)
+
+type user struct {
+	Name string
+	Age  int
+	Tags tags
+}
+
+func (u user) MarshalLogObject(d *log.Dict) {
+	d.Str("name", u.Name).Int("age", u.Age).Array("tags", u.Tags)
+}
+
+type tags []string
+
+func (t tags) MarshalLogArray(a *log.Array) {
+	for _, tag := range t {
+		a.Str(tag)
+	}
+}
+
+type users []user
+
+func (u users) MarshalLogArray(a *log.Array) {
+	for _, user := range u {
+		a.Object(user)
+	}
+}
+
+type point struct {
+	X, Y int
+}
+
+func (p *point) MarshalLogObject(d *log.Dict) {
+	d.Int("x", p.X).Int("y", p.Y)
+}
+
+type points []point
+
+func (p *points) MarshalLogArray(a *log.Array) {
+	for i := range *p {
+		a.Object(&(*p)[i])
+	}
+}
+
+func TestObject(t *testing.T) {
+	logger := log.New()
+	h := memory.New()
+	logger.AddHandler(h, log.AllLevels...)
+
+	john := user{Name: "john", Age: 18, Tags: tags{"admin", "dev"}}
+	logger.Object("user", john).Info("hello")
+	assert.Equal(t, `{"user":{"name":"john","age":18,"tags":["admin","dev"]},"level":"INFO","msg":"hello"}`+"\n", string(h.Out))
+
+	logger.Str("app", "santa").Object("user", nil).Info("nil")
+	assert.Equal(t, `{"app":"santa","user":null,"level":"INFO","msg":"nil"}`+"\n", string(h.Out))
+
+	var p *point
+	logger.Object("point", p).Info("typed nil")
+	assert.Equal(t, `{"point":null,"level":"INFO","msg":"typed nil"}`+"\n", string(h.Out))
+
+	logger.Dict("req", func(d *log.Dict) {
+		d.Object("user", user{Name: "mary"})
+	}).Info("dict")
+	assert.Equal(t, `{"req":{"user":{"name":"mary","age":0,"tags":[]}},"level":"INFO","msg":"dict"}`+"\n", string(h.Out))
+
+	h2 := memory.New()
+	log.AddHandler(h2, log.AllLevels...)
+	defer func() {
+		_ = log.RemoveHandler(h2)
+	}()
+	log.Object("user", john).Info("default")
+	assert.Contains(t, string(h2.Out), `"user":{"name":"john","age":18,"tags":["admin","dev"]},"level":"INFO","msg":"default"}`)
+}
+
+func TestArray(t *testing.T) {
+	logger := log.New()
+	h := memory.New()
+	logger.AddHandler(h, log.AllLevels...)
+
+	logger.Array("users", users{{Name: "john", Age: 18}, {Name: "mary", Age: 20}}).Info("hello")
+	assert.Equal(t, `{"users":[{"name":"john","age":18,"tags":[]},{"name":"mary","age":20,"tags":[]}],"level":"INFO","msg":"hello"}`+"\n", string(h.Out))
+
+	logger.Array("empty", tags{}).Info("empty")
+	assert.Equal(t, `{"empty":[],"level":"INFO","msg":"empty"}`+"\n", string(h.Out))
+
+	var p *points
+	logger.Array("points", p).Info("typed nil")
+	assert.Equal(t, `{"points":null,"level":"INFO","msg":"typed nil"}`+"\n", string(h.Out))
+}
@@ -0,0 +1,114 @@
+package log
+
+import (
+	"fmt"
+	"os"
+	"strings"
+	"sync/atomic"
+)
+
+// Named returns a blank context with the name.  The name is added as logger field,
+// and a dot separates the levels of the hierarchy, e.g. "db.pool" is a child of "db".
+func (l *Logger) Named(name string) Context {
+	c := newContext(l)
+	return c.Named(name)
+}
+
+// SetFilter configures the minimum level of the logger and its named contexts by
+// comma-separated directives, e.g. "info,db=debug,db.pool=warn".
+//
+// A directive without name changes the minimum level of the logger.  A named directive
+// applies to the name and all of its children unless a more specific directive exists.
+// The named directives which were set before are replaced.
+func (l *Logger) SetFilter(directives string) error {
+	level, hasLevel, nameLevels, err := parseFilter(directives)
+	if err != nil {
+		return err
+	}
+
+	if hasLevel {
+		atomic.StoreUint32(&l.level, uint32(level))
+	}
+	l.nameLevels.Store(nameLevels)
+	return nil
+}
+
+// SetFilterFromEnv configures the logger by the directives in the environment variable,
+// e.g. LOG_FILTER=info,db=debug.  Nothing is changed when the variable is empty.
+func (l *Logger) SetFilterFromEnv(key string) error {
+	directives := os.Getenv(key)
+	if len(directives) == 0 {
+		return nil
+	}
+	return l.SetFilter(directives)
+}
+
+// enabledFor reports whether the entry of the name and level should be written.  The most
+// specific directive of the name is used, and the logger's level is the fallback.
+func (l *Logger) enabledFor(name string, level Level) bool {
+	if len(name) > 0 {
+		nameLevels := l.nameLevels.Load().(map[string]Level)
+		if len(nameLevels) > 0 {
+			for {
+				min, ok := nameLevels[name]
+				if ok {
+					return level >= min
+				}
+
+				idx := strings.LastIndexByte(name, '.')
+				if idx < 0 {
+					break
+				}
+				name = name[:idx]
+			}
+		}
+	}
+
+	return l.Enabled(level)
+}
+
+func parseFilter(directives string) (Level, bool, map[string]Level, error) {
+	var level Level
+	hasLevel := false
+	nameLevels := map[string]Level{}
+
+	for _, directive := range strings.Split(directives, ",") {
+		directive = strings.TrimSpace(directive)
+		if len(directive) == 0 {
+			continue
+		}
+
+		idx := strings.IndexByte(directive, '=')
+		if idx < 0 {
+			lvl, err := parseMinLevel(directive)
+			if err != nil {
+				return level, false, nil, fmt.Errorf("log: invalid filter directive %q: %w", directive, err)
+			}
+			level = lvl
+			hasLevel = true
+			continue
+		}
+
+		name := strings.TrimSpace(directive[:idx])
+		if len(name) == 0 {
+			return level, false, nil, fmt.Errorf("log: invalid filter directive %q: name is empty", directive)
+		}
+
+		lvl, err := parseMinLevel(strings.TrimSpace(directive[idx+1:]))
+		if err != nil {
+			return level, false, nil, fmt.Errorf("log: invalid filter directive %q: %w", directive, err)
+		}
+		nameLevels[name] = lvl
+	}
+
+	return level, hasLevel, nameLevels, nil
+}
+
+// parseMinLevel parses the level of a directive, which must be a valid minimum level
+func parseMinLevel(name string) (Level, error) {
+	level, err := ParseLevel(name)
+	if err != nil {
+		return level, err
+	}
+	return level, validMinLevel(level)
+}
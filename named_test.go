@@ -0,0 +1,72 @@
+package log_test
+
+import (
+	"os"
+	"testing"
+
+	"github.com/jasonsoft/log/v2"
+	"github.com/jasonsoft/log/v2/handlers/memory"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNamed(t *testing.T) {
+	logger := log.New()
+	h := memory.New()
+	logger.AddHandler(h, log.AllLevels...)
+
+	db := logger.Named("db").Str("app", "santa")
+	db.Info("connected")
+	assert.Equal(t, `{"app":"santa","logger":"db","level":"INFO","msg":"connected"}`+"\n", string(h.Out))
+
+	db.Named("pool").Debug("acquire")
+	assert.Equal(t, `{"app":"santa","logger":"db.pool","level":"DEBUG","msg":"acquire"}`+"\n", string(h.Out))
+}
+
+func TestSetFilter(t *testing.T) {
+	logger := log.New()
+	h := memory.New()
+	logger.AddHandler(h, log.AllLevels...)
+
+	err := logger.SetFilter("info, db=debug, db.pool=warn")
+	assert.NoError(t, err)
+	assert.Equal(t, log.InfoLevel, logger.Level())
+
+	logger.Debug("root debug")
+	assert.Equal(t, "", string(h.Out))
+
+	logger.Named("db").Debug("db debug")
+	assert.Equal(t, `{"logger":"db","level":"DEBUG","msg":"db debug"}`+"\n", string(h.Out))
+
+	logger.Named("db.conn").Debug("conn debug")
+	assert.Equal(t, `{"logger":"db.conn","level":"DEBUG","msg":"conn debug"}`+"\n", string(h.Out))
+
+	logger.Named("db").Named("pool").Info("pool info")
+	assert.Equal(t, `{"logger":"db.conn","level":"DEBUG","msg":"conn debug"}`+"\n", string(h.Out))
+
+	logger.Named("db.pool.idle").Warn("idle warn")
+	assert.Equal(t, `{"logger":"db.pool.idle","level":"WARN","msg":"idle warn"}`+"\n", string(h.Out))
+
+	logger.Named("http").Debug("http debug")
+	assert.Equal(t, `{"logger":"db.pool.idle","level":"WARN","msg":"idle warn"}`+"\n", string(h.Out))
+
+	err = logger.SetFilter("db=verbose")
+	assert.Error(t, err)
+
+	err = logger.SetFilter("=debug")
+	assert.Error(t, err)
+}
+
+func TestSetFilterFromEnv(t *testing.T) {
+	logger := log.New()
+
+	os.Setenv("LOG_FILTER_TEST", "warn,db=debug")
+	defer os.Unsetenv("LOG_FILTER_TEST")
+
+	err := logger.SetFilterFromEnv("LOG_FILTER_TEST")
+	assert.NoError(t, err)
+	assert.Equal(t, log.WarnLevel, logger.Level())
+
+	err = logger.SetFilterFromEnv("LOG_FILTER_NOT_EXIST")
+	assert.NoError(t, err)
+	assert.Equal(t, log.WarnLevel, logger.Level())
+}
@@ -0,0 +1,8 @@
+//go:build race
+// +build race
+
+package log
+
+// raceEnabled reports whether the tests run with the race detector, which makes sync.Pool
+// drop items randomly, so allocations can't be measured
+const raceEnabled = true
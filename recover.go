@@ -0,0 +1,125 @@
+package log
+
+import (
+	"context"
+	"fmt"
+	"runtime"
+	"strings"
+)
+
+// RecoverConfig configures Recover and Go
+type RecoverConfig struct {
+	// Repanic panics again with the recovered value after the panic is logged
+	Repanic bool
+}
+
+// SetRecover configures Recover and Go.  The recovered panics aren't re-panicked by default.
+func (l *Logger) SetRecover(config RecoverConfig) {
+	l.recovery.Store(config)
+}
+
+// Recover logs the panic at PanicLevel with the recovered value and the stack trace.
+// It must be called by defer directly, e.g. defer logger.Recover()
+func (l *Logger) Recover() {
+	if r := recover(); r != nil {
+		l.logPanic(context.Background(), r)
+	}
+}
+
+// RecoverContext is like Recover, and the entry has the fields of Ctx(ctx)
+func (l *Logger) RecoverContext(ctx context.Context) {
+	if r := recover(); r != nil {
+		l.logPanic(ctx, r)
+	}
+}
+
+// Go runs fn in a new goroutine, and the panic of fn is logged by RecoverContext
+func (l *Logger) Go(ctx context.Context, fn func(ctx context.Context)) {
+	go func() {
+		defer l.RecoverContext(ctx)
+		fn(ctx)
+	}()
+}
+
+// Recover logs the panic by the default logger.  It must be called by defer directly, e.g. defer log.Recover()
+func Recover() {
+	if r := recover(); r != nil {
+		_logger.logPanic(context.Background(), r)
+	}
+}
+
+// RecoverContext is like Recover, and the entry has the fields of Ctx(ctx)
+func RecoverContext(ctx context.Context) {
+	if r := recover(); r != nil {
+		_logger.logPanic(ctx, r)
+	}
+}
+
+// Go runs fn in a new goroutine, and the panic of fn is logged by the default logger
+func Go(ctx context.Context, fn func(ctx context.Context)) {
+	_logger.Go(ctx, fn)
+}
+
+// SetRecover configures Recover and Go of the default logger
+func SetRecover(config RecoverConfig) {
+	_logger.SetRecover(config)
+}
+
+func (l *Logger) logPanic(ctx context.Context, r interface{}) {
+	c := l.Ctx(ctx)
+	e := newEntry(l, c.buf)
+	e.name = c.name
+	e.span = c.span
+	stack := panicStack(l.stackConfig())
+	if len(stack) > 0 {
+		e.pc = stack[0]
+	}
+
+	names := l.FieldNames()
+	if err, ok := r.(error); ok {
+		e.buf = enc.AppendKey(e.buf, names.Error)
+		e.buf = l.appendError(e.buf, err)
+	}
+	e.buf = enc.AppendKey(e.buf, "panic")
+	e.buf = enc.AppendString(e.buf, fmt.Sprint(r))
+	e.buf = enc.AppendKey(e.buf, names.Stack)
+	e.buf = l.appendStackTrace(e.buf, stack)
+
+	e.Level = PanicLevel
+	e.Message = "recovered from panic"
+	handler(e)
+
+	if l.recovery.Load().(RecoverConfig).Repanic {
+		panic(r)
+	}
+}
+
+// logPackage is the prefix of the funcs of this package, e.g. "github.com/jasonsoft/log/v2."
+var logPackage = func() string {
+	pc, _, _, _ := runtime.Caller(0)
+	name := runtime.FuncForPC(pc).Name()
+	idx := strings.LastIndexByte(name, '/')
+	return name[:idx+strings.IndexByte(name[idx:], '.')+1]
+}()
+
+// panicStack returns the stack of a recovered panic, starting at the panicking func.  The frames
+// above it which belong to the runtime, e.g. runtime.gopanic or the map assignment of a nil map,
+// or to this package, e.g. Recover, are dropped.
+func panicStack(config StackConfig) []uintptr {
+	// room for the frames of the runtime and this package above the panicking func
+	pcs := make([]uintptr, 32+config.Depth)
+	pcs = pcs[:runtime.Callers(2, pcs)]
+	for i := range pcs {
+		frame, _ := runtime.CallersFrames(pcs[i : i+1]).Next()
+		if !isPanicFrame(frame.Function) {
+			return pcs[i:]
+		}
+	}
+	return nil
+}
+
+func isPanicFrame(function string) bool {
+	return strings.HasPrefix(function, "runtime.") ||
+		strings.HasPrefix(function, "internal/runtime/") ||
+		strings.HasPrefix(function, logPackage)
+}
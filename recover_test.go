@@ -0,0 +1,93 @@
+package log_test
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/jasonsoft/log/v2"
+	"github.com/jasonsoft/log/v2/handlers/memory"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRecover(t *testing.T) {
+	logger := log.New()
+	h := memory.New()
+	logger.AddHandler(h, log.AllLevels...)
+	logger.SetCaller(log.CallerConfig{Enabled: true})
+
+	var l int
+	func() {
+		defer logger.Recover()
+		l = line() + 1
+		panic("boom")
+	}()
+
+	out := string(h.Out)
+	assert.Contains(t, out, `"panic":"boom"`)
+	assert.Contains(t, out, `"level":"PANIC","msg":"recovered from panic"`)
+	assert.Contains(t, out, fmt.Sprintf(`/recover_test.go:%d"`, l))
+	assert.Contains(t, out, "TestRecover.func1")
+}
+
+type chanHandler chan string
+
+func (h chanHandler) BeforeWriting(e *log.Entry) error {
+	return nil
+}
+
+func (h chanHandler) Write(bytes []byte) error {
+	h <- string(bytes)
+	return nil
+}
+
+func TestGo(t *testing.T) {
+	logger := log.New()
+	h := make(chanHandler, 1)
+	logger.AddHandler(h, log.AllLevels...)
+
+	ctx := logger.Str("request_id", "abc").WithContext(context.Background())
+	logger.Go(ctx, func(ctx context.Context) {
+		panic(errors.New("oops"))
+	})
+
+	out := <-h
+	assert.Contains(t, out, `{"request_id":"abc","error":"oops","panic":"oops"`)
+	assert.Contains(t, out, "TestGo.func1")
+}
+
+func TestRecoverRepanic(t *testing.T) {
+	logger := log.New()
+	h := memory.New()
+	logger.AddHandler(h, log.AllLevels...)
+	logger.SetRecover(log.RecoverConfig{Repanic: true})
+
+	assert.PanicsWithValue(t, "boom", func() {
+		defer logger.Recover()
+		panic("boom")
+	})
+	assert.Contains(t, string(h.Out), `"panic":"boom"`)
+}
+
+func TestRecoverRuntimeError(t *testing.T) {
+	logger := log.New()
+	h := memory.New()
+	logger.AddHandler(h, log.AllLevels...)
+	logger.SetCaller(log.CallerConfig{Enabled: true, Func: true})
+	logger.SetStackTrace(log.StackConfig{Frames: true})
+
+	var l int
+	func() {
+		defer logger.Recover()
+		var m map[string]int
+		l = line() + 1
+		m["a"] = 1
+	}()
+
+	out := string(h.Out)
+	assert.Contains(t, out, `"panic":"assignment to entry in nil map"`)
+	assert.Contains(t, out, fmt.Sprintf(`/recover_test.go:%d","func":"github.com/jasonsoft/log/v2_test.TestRecoverRuntimeError.func1"`, l))
+	assert.Contains(t, out, `"stack_trace":[{"func":"github.com/jasonsoft/log/v2_test.TestRecoverRuntimeError.func1"`)
+	assert.NotContains(t, out, "(*Logger).Recover")
+}
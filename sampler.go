@@ -0,0 +1,98 @@
+package log
+
+import (
+	"sync/atomic"
+	"time"
+)
+
+const samplerCountersPerLevel = 1024
+
+type samplerCounter struct {
+	resetAt int64
+	count   uint64
+}
+
+func (c *samplerCounter) incr(now int64, interval time.Duration) uint64 {
+	resetAt := atomic.LoadInt64(&c.resetAt)
+	if resetAt > now {
+		return atomic.AddUint64(&c.count, 1)
+	}
+
+	// a new interval starts
+	atomic.StoreUint64(&c.count, 1)
+	if !atomic.CompareAndSwapInt64(&c.resetAt, resetAt, now+int64(interval)) {
+		// another goroutine has started the interval
+		return atomic.AddUint64(&c.count, 1)
+	}
+	return 1
+}
+
+// Sampler limits the number of entries which have the same level and message.  Within
+// each interval, the first entries are written, and then one of every thereafter entries
+// is written.  Panic and fatal entries are never sampled out.
+//
+// Entries are grouped by a hash of the message, so a few different messages may share
+// the same counter.  Sampler is goroutine safe.
+type Sampler struct {
+	interval   time.Duration
+	first      uint64
+	thereafter uint64
+	now        func() time.Time
+	counters   [TraceLevel + 1][samplerCountersPerLevel]samplerCounter
+
+	passed  uint64
+	dropped uint64
+}
+
+// NewSampler creates a sampler which writes the first entries with the same level and message
+// in every interval, and then writes one of every thereafter entries.  If thereafter is zero,
+// all entries after the first ones are dropped until the next interval.
+func NewSampler(interval time.Duration, first int, thereafter int) *Sampler {
+	return &Sampler{
+		interval:   interval,
+		first:      uint64(first),
+		thereafter: uint64(thereafter),
+		now:        time.Now,
+	}
+}
+
+// Sample reports whether the entry with the level and message should be written
+func (s *Sampler) Sample(level Level, msg string) bool {
+	if level == PanicLevel || level == FatalLevel || level > TraceLevel {
+		return true
+	}
+
+	counter := &s.counters[level][fnv32a(msg)%samplerCountersPerLevel]
+	n := counter.incr(s.now().UnixNano(), s.interval)
+	if n <= s.first || (s.thereafter > 0 && (n-s.first)%s.thereafter == 0) {
+		atomic.AddUint64(&s.passed, 1)
+		return true
+	}
+
+	atomic.AddUint64(&s.dropped, 1)
+	return false
+}
+
+// Passed returns the number of entries which were written
+func (s *Sampler) Passed() uint64 {
+	return atomic.LoadUint64(&s.passed)
+}
+
+// Dropped returns the number of entries which were sampled out
+func (s *Sampler) Dropped() uint64 {
+	return atomic.LoadUint64(&s.dropped)
+}
+
+// fnv32a is the 32-bit FNV-1a hash, which doesn't allocate like hash/fnv does.
+func fnv32a(s string) uint32 {
+	const (
+		offset32 = 2166136261
+		prime32  = 16777619
+	)
+	hash := uint32(offset32)
+	for i := 0; i < len(s); i++ {
+		hash ^= uint32(s[i])
+		hash *= prime32
+	}
+	return hash
+}
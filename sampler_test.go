@@ -0,0 +1,81 @@
+package log
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSampler(t *testing.T) {
+	now := time.Date(2020, 8, 26, 0, 0, 0, 0, time.UTC)
+	s := NewSampler(time.Second, 2, 3)
+	s.now = func() time.Time { return now }
+
+	results := []bool{}
+	for i := 0; i < 8; i++ {
+		results = append(results, s.Sample(InfoLevel, "hello"))
+	}
+	assert.Equal(t, []bool{true, true, false, false, true, false, false, true}, results)
+	assert.Equal(t, uint64(4), s.Passed())
+	assert.Equal(t, uint64(4), s.Dropped())
+
+	// other level and message have their own counters
+	assert.True(t, s.Sample(WarnLevel, "hello"))
+	assert.True(t, s.Sample(InfoLevel, "world"))
+
+	// fatal and panic are never sampled out
+	for i := 0; i < 5; i++ {
+		assert.True(t, s.Sample(FatalLevel, "hello"))
+	}
+
+	// counters are reset in next interval
+	now = now.Add(time.Second)
+	assert.True(t, s.Sample(InfoLevel, "hello"))
+	assert.True(t, s.Sample(InfoLevel, "hello"))
+	assert.False(t, s.Sample(InfoLevel, "hello"))
+}
+
+func TestLoggerSampler(t *testing.T) {
+	logger := New()
+	count := 0
+	logger.AddHandler(&countHandler{count: &count}, AllLevels...)
+
+	s := NewSampler(time.Minute, 3, 0)
+	logger.SetSampler(s)
+	for i := 0; i < 10; i++ {
+		logger.Info("hello")
+	}
+	assert.Equal(t, 3, count)
+	assert.Equal(t, uint64(7), s.Dropped())
+
+	logger.SetSampler(nil)
+	logger.Info("hello")
+	assert.Equal(t, 4, count)
+}
+
+func BenchmarkSamplerDropped(b *testing.B) {
+	logger := New()
+	count := 0
+	logger.AddHandler(&countHandler{count: &count}, AllLevels...)
+	logger.SetSampler(NewSampler(time.Hour, 1, 0))
+
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		logger.Info("hello world")
+	}
+}
+
+type countHandler struct {
+	count *int
+}
+
+func (h *countHandler) BeforeWriting(e *Entry) error {
+	return nil
+}
+
+func (h *countHandler) Write(bytes []byte) error {
+	*h.count++
+	return nil
+}
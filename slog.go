@@ -0,0 +1,221 @@
+//go:build go1.21
+// +build go1.21
+
+package log
+
+import (
+	"bytes"
+	"context"
+	"log/slog"
+	"time"
+)
+
+// slogHandler writes the records of slog through the handlers of the logger
+type slogHandler struct {
+	logger *Logger
+	name   string
+	span   SpanContext // the span of Context.SlogHandler, which is used when the record's ctx has none
+	// attrs is the JSON object of the attributes which are added by WithAttrs, including the opened groups
+	attrs      []byte
+	groups     []string
+	openGroups int // the number of groups which have been opened in attrs
+}
+
+// SlogHandler returns a slog.Handler which writes the records through the handlers of the logger, so
+// slog.New(logger.SlogHandler()) can be used by libraries.  The fields of Ctx(ctx) of the record
+// are added to the entry.
+func (l *Logger) SlogHandler() slog.Handler {
+	return &slogHandler{
+		logger: l,
+		attrs:  enc.AppendBeginMarker(nil),
+	}
+}
+
+// SlogHandler returns a slog.Handler which writes the records through the handlers of the default logger
+func SlogHandler() slog.Handler {
+	return _logger.SlogHandler()
+}
+
+// SlogHandler returns a slog.Handler which writes the records with the name, the fields and the
+// span of the context.  The span of the record's ctx is used instead when it has one.
+func (c Context) SlogHandler() slog.Handler {
+	l := c.getLogger()
+	h := l.SlogHandler().(*slogHandler)
+	h.name = c.name
+	h.span = c.span
+
+	// the default fields of the logger are added by Ctx, so only the fields of the context are carried
+	l.rwMutex.RLock()
+	defaults := l.buf
+	l.rwMutex.RUnlock()
+	var fields []byte
+	if len(c.buf) > 0 {
+		fields = c.buf[1:]
+	}
+	if len(defaults) > 0 && bytes.HasPrefix(c.buf, defaults) {
+		fields = c.buf[len(defaults):]
+	}
+	h.attrs = append(h.attrs, bytes.TrimPrefix(fields, []byte(","))...)
+	return h
+}
+
+// FromSlogLevel converts the slog level to the level of the logger.  The levels above
+// slog.LevelError are converted to ErrorLevel, so the adapter never panics or exits.
+func FromSlogLevel(level slog.Level) Level {
+	switch {
+	case level < slog.LevelInfo:
+		return DebugLevel
+	case level < slog.LevelWarn:
+		return InfoLevel
+	case level < slog.LevelError:
+		return WarnLevel
+	default:
+		return ErrorLevel
+	}
+}
+
+// ToSlogLevel converts the level to the slog level.  PanicLevel and FatalLevel are above slog.LevelError.
+func ToSlogLevel(level Level) slog.Level {
+	switch level {
+	case DebugLevel:
+		return slog.LevelDebug
+	case WarnLevel:
+		return slog.LevelWarn
+	case ErrorLevel:
+		return slog.LevelError
+	case PanicLevel:
+		return slog.LevelError + 4
+	case FatalLevel:
+		return slog.LevelError + 8
+	default:
+		return slog.LevelInfo
+	}
+}
+
+// Enabled implements slog.Handler.
+func (h *slogHandler) Enabled(ctx context.Context, level slog.Level) bool {
+	return h.logger.enabledFor(h.name, FromSlogLevel(level))
+}
+
+// WithAttrs implements slog.Handler.
+func (h *slogHandler) WithAttrs(attrs []slog.Attr) slog.Handler {
+	if len(attrs) == 0 {
+		return h
+	}
+
+	h2 := *h
+	h2.attrs = make([]byte, len(h.attrs), len(h.attrs)+64)
+	copy(h2.attrs, h.attrs)
+	for _, group := range h.groups[h.openGroups:] {
+		h2.attrs = enc.AppendKey(h2.attrs, group)
+		h2.attrs = enc.AppendBeginMarker(h2.attrs)
+	}
+	h2.openGroups = len(h.groups)
+	for _, a := range attrs {
+		h2.attrs = appendSlogAttr(h2.attrs, a)
+	}
+	return &h2
+}
+
+// WithGroup implements slog.Handler.
+func (h *slogHandler) WithGroup(name string) slog.Handler {
+	if len(name) == 0 {
+		return h
+	}
+
+	h2 := *h
+	h2.groups = append(h.groups[:len(h.groups):len(h.groups)], name)
+	return &h2
+}
+
+// Handle implements slog.Handler.
+func (h *slogHandler) Handle(ctx context.Context, r slog.Record) error {
+	c := h.logger.Ctx(ctx)
+	e := newEntry(h.logger, c.buf)
+	e.name = h.name
+	e.span = c.span
+	if !e.span.IsValid() {
+		e.span = h.span
+	}
+	e.errStack = c.errStack
+	// the caller is the pc of the record.  When it is 0, e.g. the record is created by a wrapper
+	// without a pc, the frames are counted: Handle, the log func of slog.Logger and its caller
+	e.pc = r.PC
+	e.callerSkip = 2
+
+	// attrs begins with '{', so it is appended as the rest of the entry's object
+	if len(h.attrs) > 1 {
+		e.buf = enc.AppendObjectData(e.buf, h.attrs[1:])
+	}
+
+	openGroups := h.openGroups
+	if r.NumAttrs() > 0 {
+		for _, group := range h.groups[h.openGroups:] {
+			e.buf = enc.AppendKey(e.buf, group)
+			e.buf = enc.AppendBeginMarker(e.buf)
+		}
+		openGroups = len(h.groups)
+		r.Attrs(func(a slog.Attr) bool {
+			e.buf = appendSlogAttr(e.buf, a)
+			return true
+		})
+	}
+	for i := 0; i < openGroups; i++ {
+		e.buf = enc.AppendEndMarker(e.buf)
+	}
+
+	e.Level = FromSlogLevel(r.Level)
+	e.Message = r.Message
+	handler(e)
+	return nil
+}
+
+func appendSlogAttr(buf []byte, a slog.Attr) []byte {
+	a.Value = a.Value.Resolve()
+	if a.Equal(slog.Attr{}) {
+		return buf
+	}
+
+	if a.Value.Kind() == slog.KindGroup {
+		attrs := a.Value.Group()
+		if len(attrs) == 0 {
+			return buf
+		}
+		if len(a.Key) > 0 {
+			buf = enc.AppendKey(buf, a.Key)
+			buf = enc.AppendBeginMarker(buf)
+		}
+		for _, ga := range attrs {
+			buf = appendSlogAttr(buf, ga)
+		}
+		if len(a.Key) > 0 {
+			buf = enc.AppendEndMarker(buf)
+		}
+		return buf
+	}
+
+	buf = enc.AppendKey(buf, a.Key)
+	switch a.Value.Kind() {
+	case slog.KindString:
+		buf = enc.AppendString(buf, a.Value.String())
+	case slog.KindInt64:
+		buf = enc.AppendInt64(buf, a.Value.Int64())
+	case slog.KindUint64:
+		buf = enc.AppendUint64(buf, a.Value.Uint64())
+	case slog.KindFloat64:
+		buf = enc.AppendFloat64(buf, a.Value.Float64())
+	case slog.KindBool:
+		buf = enc.AppendBool(buf, a.Value.Bool())
+	case slog.KindDuration:
+		buf = enc.AppendDuration(buf, a.Value.Duration(), time.Millisecond, false)
+	case slog.KindTime:
+		buf = enc.AppendTime(buf, a.Value.Time(), time.RFC3339)
+	default:
+		if err, ok := a.Value.Any().(error); ok {
+			buf = enc.AppendString(buf, err.Error())
+		} else {
+			buf = enc.AppendInterface(buf, a.Value.Any())
+		}
+	}
+	return buf
+}
@@ -0,0 +1,121 @@
+//go:build go1.21
+// +build go1.21
+
+package log_test
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"runtime"
+	"testing"
+	"time"
+
+	"github.com/jasonsoft/log/v2"
+	"github.com/jasonsoft/log/v2/handlers/memory"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSlogHandler(t *testing.T) {
+	logger := log.New()
+	h := memory.New()
+	logger.AddHandler(h, log.AllLevels...)
+	logger.SetLevel(log.InfoLevel)
+
+	sl := slog.New(logger.SlogHandler())
+
+	sl.Info("hello", "user_id", 1, "ok", true, "took", 1500*time.Millisecond, "err", errors.New("oops"))
+	assert.Equal(t, `{"user_id":1,"ok":true,"took":1500,"err":"oops","level":"INFO","msg":"hello"}`+"\n", string(h.Out))
+
+	sl.Debug("dropped")
+	assert.Contains(t, string(h.Out), `"msg":"hello"`)
+
+	sl.Log(context.Background(), slog.LevelError+4, "above error")
+	assert.Contains(t, string(h.Out), `"level":"ERROR","msg":"above error"`)
+}
+
+func TestSlogHandlerGroups(t *testing.T) {
+	logger := log.New()
+	h := memory.New()
+	logger.AddHandler(h, log.AllLevels...)
+
+	sl := slog.New(logger.SlogHandler()).With("app", "santa").WithGroup("req").With("id", "abc").WithGroup("user")
+
+	sl.Info("with attrs", "name", "john", slog.Group("geo", "city", "taipei"))
+	assert.Equal(t, `{"app":"santa","req":{"id":"abc","user":{"name":"john","geo":{"city":"taipei"}}},"level":"INFO","msg":"with attrs"}`+"\n", string(h.Out))
+
+	sl.Info("without attrs")
+	assert.Equal(t, `{"app":"santa","req":{"id":"abc"},"level":"INFO","msg":"without attrs"}`+"\n", string(h.Out))
+}
+
+func TestSlogHandlerContext(t *testing.T) {
+	logger := log.New()
+	h := memory.New()
+	logger.AddHandler(h, log.AllLevels...)
+	logger.SetCaller(log.CallerConfig{Enabled: true})
+
+	ctx := logger.Str("request_id", "abc").WithContext(context.Background())
+	sl := slog.New(logger.Named("db").SlogHandler())
+
+	sl.InfoContext(ctx, "query", "rows", 2)
+	l := line() - 1
+	assert.Contains(t, string(h.Out), `{"request_id":"abc","rows":2,"logger":"db"`)
+	assert.Contains(t, string(h.Out), fmt.Sprintf(`/slog_test.go:%d"`, l))
+}
+
+func TestSlogHandlerExtractors(t *testing.T) {
+	logger := log.New()
+	h := memory.New()
+	logger.AddHandler(h, log.AllLevels...)
+	logger.AddContextExtractor(func(ctx context.Context, c log.Context) log.Context {
+		if tenant, ok := ctx.Value(tenantKey{}).(string); ok {
+			c = c.Str("tenant", tenant)
+		}
+		return c
+	})
+
+	ctx := context.WithValue(context.Background(), tenantKey{}, "a")
+	ctx = logger.Str("request_id", "abc").WithContext(ctx)
+	slog.New(logger.SlogHandler()).InfoContext(ctx, "from slog")
+	assert.Equal(t, `{"request_id":"abc","tenant":"a","level":"INFO","msg":"from slog"}`+"\n", string(h.Out))
+}
+
+func TestContextSlogHandler(t *testing.T) {
+	logger := log.New()
+	h := memory.New()
+	logger.AddHandler(h, log.AllLevels...)
+	logger.Str("app", "santa").SaveToDefault()
+
+	ctx, err := log.ContextWithTraceparent(context.Background(), traceparent)
+	assert.NoError(t, err)
+	sl := slog.New(logger.Ctx(ctx).Str("job", "sync").SlogHandler()).With("attempt", 1)
+
+	sl.Info("started", "items", 3)
+	assert.Equal(t, `{"app":"santa","job":"sync","attempt":1,"items":3,"trace_id":"4bf92f3577b34da6a3ce929d0e0e4736","span_id":"00f067aa0ba902b7","trace_flags":"01","level":"INFO","msg":"started"}`+"\n", string(h.Out))
+}
+
+// slogHelper logs a record whose pc is the caller of slogHelper, as a wrapper of slog does
+func slogHelper(h slog.Handler, msg string) {
+	var pcs [1]uintptr
+	runtime.Callers(2, pcs[:]) // skip runtime.Callers and slogHelper
+	_ = h.Handle(context.Background(), slog.NewRecord(time.Now(), slog.LevelInfo, msg, pcs[0]))
+}
+
+func TestSlogHandlerRecordPC(t *testing.T) {
+	logger := log.New()
+	h := memory.New()
+	logger.AddHandler(h, log.AllLevels...)
+	logger.SetCaller(log.CallerConfig{Enabled: true, Func: true})
+
+	slogHelper(logger.SlogHandler(), "from helper")
+	l := line() - 1
+	assert.Contains(t, string(h.Out), fmt.Sprintf(`/slog_test.go:%d","func":"github.com/jasonsoft/log/v2_test.TestSlogHandlerRecordPC"`, l))
+}
+
+func TestSlogLevel(t *testing.T) {
+	for _, level := range []log.Level{log.DebugLevel, log.InfoLevel, log.WarnLevel, log.ErrorLevel} {
+		assert.Equal(t, level, log.FromSlogLevel(log.ToSlogLevel(level)))
+	}
+	assert.Equal(t, log.ErrorLevel, log.FromSlogLevel(log.ToSlogLevel(log.FatalLevel)))
+}
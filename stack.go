@@ -0,0 +1,159 @@
+package log
+
+import (
+	"fmt"
+	"reflect"
+	"runtime"
+	"strings"
+)
+
+// defaultStackDepth is the maximum number of frames when StackConfig.Depth isn't set
+const defaultStackDepth = 50
+
+// StackConfig configures the stack trace field
+type StackConfig struct {
+	// Frames writes the stack trace as an array of {"func", "file", "line"} objects.
+	// Default: a string with a line per frame
+	Frames bool
+	// Depth is the maximum number of frames.  Default: 50
+	Depth int
+	// Skip is the number of extra frames to skip.  It is useful when the logger is wrapped by another package.
+	Skip int
+	// Filters drops the frames whose function starts with one of the prefixes,
+	// e.g. "github.com/jasonsoft/log/v2." drops the frames of the logger
+	Filters []string
+}
+
+// SetStackTrace configures the stack trace field
+func (l *Logger) SetStackTrace(config StackConfig) {
+	if config.Depth <= 0 {
+		config.Depth = defaultStackDepth
+	}
+	l.stack.Store(config)
+}
+
+// SetStackTrace configures the stack trace field of the default logger
+func SetStackTrace(config StackConfig) {
+	_logger.SetStackTrace(config)
+}
+
+func (l *Logger) stackConfig() StackConfig {
+	return l.stack.Load().(StackConfig)
+}
+
+// appendStackTrace appends the stack trace of the caller of the func which calls appendStackTrace.
+// The given stack, e.g. the stack carried by an error of Err or the stack of a recovered panic,
+// is used instead when it isn't nil.
+func (l *Logger) appendStackTrace(buf []byte, stack []uintptr) []byte {
+	config := l.stackConfig()
+	if stack != nil {
+		return appendFrames(buf, config, runtime.CallersFrames(stack))
+	}
+
+	// frames: runtime.Callers, appendStackTrace and its caller
+	pcs := make([]uintptr, config.Depth+config.Skip)
+	n := runtime.Callers(3+config.Skip, pcs)
+	return appendFrames(buf, config, runtime.CallersFrames(pcs[:n]))
+}
+
+// appendFrames appends the frames which aren't dropped by the filters, up to the depth
+func appendFrames(buf []byte, config StackConfig, frames *runtime.Frames) []byte {
+	var b strings.Builder
+	if config.Frames {
+		buf = enc.AppendArrayStart(buf)
+	}
+
+	count := 0
+	for count < config.Depth {
+		frame, more := frames.Next()
+		if frame.PC != 0 && keepFrame(config, frame) {
+			if config.Frames {
+				if count > 0 {
+					buf = enc.AppendArrayDelim(buf)
+				}
+				buf = appendFrame(buf, frame.Function, frame.File, frame.Line)
+			} else {
+				_, _ = b.WriteString(fmt.Sprintf("\n\tFile: %s, Line: %d. Function: %s", frame.File, frame.Line, frame.Function))
+			}
+			count++
+		}
+		if !more {
+			break
+		}
+	}
+
+	if config.Frames {
+		return enc.AppendArrayEnd(buf)
+	}
+	return enc.AppendString(buf, b.String())
+}
+
+func keepFrame(config StackConfig, frame runtime.Frame) bool {
+	if strings.Contains(frame.File, "runtime/") {
+		return false
+	}
+	for _, prefix := range config.Filters {
+		if strings.HasPrefix(frame.Function, prefix) {
+			return false
+		}
+	}
+	return true
+}
+
+func appendFrame(buf []byte, function, file string, line int) []byte {
+	buf = enc.AppendBeginMarker(buf)
+	buf = enc.AppendKey(buf, "func")
+	buf = enc.AppendString(buf, function)
+	buf = enc.AppendKey(buf, "file")
+	buf = enc.AppendString(buf, file)
+	buf = enc.AppendKey(buf, "line")
+	buf = enc.AppendInt(buf, line)
+	return enc.AppendEndMarker(buf)
+}
+
+// StackFramer is implemented by errors which carry the stack where they were created.  Frames returns
+// the program counters as returned by runtime.Callers.  The errors of github.com/pkg/errors are
+// supported by their StackTrace method without implementing StackFramer.
+type StackFramer interface {
+	Frames() []uintptr
+}
+
+// errorStack returns the stack of the innermost error which carries a stack in the chain of err
+func errorStack(err error) []uintptr {
+	if err == nil {
+		return nil
+	}
+
+	stack := stackOf(err)
+	for _, e := range errorChain(nil, err) {
+		if pcs := stackOf(e); pcs != nil {
+			stack = pcs
+		}
+	}
+	return stack
+}
+
+// stackOf returns the stack carried by err.  Besides StackFramer, a StackTrace method which returns
+// a slice of uintptr based frames, e.g. errors.StackTrace of github.com/pkg/errors, is used, so the
+// package isn't a dependency.
+func stackOf(err error) []uintptr {
+	if framer, ok := err.(StackFramer); ok {
+		return framer.Frames()
+	}
+
+	method := reflect.ValueOf(err).MethodByName("StackTrace")
+	if !method.IsValid() {
+		return nil
+	}
+	typ := method.Type()
+	if typ.NumIn() != 0 || typ.NumOut() != 1 || typ.Out(0).Kind() != reflect.Slice || typ.Out(0).Elem().Kind() != reflect.Uintptr {
+		return nil
+	}
+
+	frames := method.Call(nil)[0]
+	pcs := make([]uintptr, frames.Len())
+	for i := range pcs {
+		pcs[i] = uintptr(frames.Index(i).Uint())
+	}
+	return pcs
+}
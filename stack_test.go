@@ -0,0 +1,159 @@
+package log_test
+
+import (
+	"encoding/json"
+	"errors"
+	"runtime"
+	"strings"
+	"testing"
+
+	"github.com/jasonsoft/log/v2"
+	"github.com/jasonsoft/log/v2/handlers/memory"
+	"github.com/stretchr/testify/assert"
+)
+
+type stackFrame struct {
+	Func string `json:"func"`
+	File string `json:"file"`
+	Line int    `json:"line"`
+}
+
+// stackHelper returns the line of its logging call
+func stackHelper(logger *log.Logger) int {
+	_, _, line, _ := runtime.Caller(0)
+	logger.Str("a", "b").StackTrace().Info("helper")
+	return line + 1
+}
+
+func TestStackTraceFrames(t *testing.T) {
+	logger := log.New()
+	h := memory.New()
+	logger.AddHandler(h, log.AllLevels...)
+	logger.SetStackTrace(log.StackConfig{Frames: true})
+
+	line := stackHelper(logger)
+
+	var out struct {
+		Stack []stackFrame `json:"stack_trace"`
+	}
+	assert.NoError(t, json.Unmarshal(h.Out, &out))
+	assert.True(t, len(out.Stack) >= 2)
+	assert.Equal(t, "github.com/jasonsoft/log/v2_test.stackHelper", out.Stack[0].Func)
+	assert.True(t, strings.HasSuffix(out.Stack[0].File, "stack_test.go"))
+	assert.Equal(t, line, out.Stack[0].Line)
+	assert.Equal(t, "github.com/jasonsoft/log/v2_test.TestStackTraceFrames", out.Stack[1].Func)
+}
+
+func TestStackTraceConfig(t *testing.T) {
+	logger := log.New()
+	h := memory.New()
+	logger.AddHandler(h, log.AllLevels...)
+
+	var out struct {
+		Stack []stackFrame `json:"stack_trace"`
+	}
+
+	// skip stackHelper and keep one frame
+	logger.SetStackTrace(log.StackConfig{Frames: true, Skip: 1, Depth: 1})
+	stackHelper(logger)
+	assert.NoError(t, json.Unmarshal(h.Out, &out))
+	assert.Len(t, out.Stack, 1)
+	assert.Equal(t, "github.com/jasonsoft/log/v2_test.TestStackTraceConfig", out.Stack[0].Func)
+
+	// the frames of the test package and the testing package are dropped
+	logger.SetStackTrace(log.StackConfig{Frames: true, Filters: []string{"github.com/jasonsoft/log/v2_test.", "testing."}})
+	stackHelper(logger)
+	assert.NoError(t, json.Unmarshal(h.Out, &out))
+	assert.Empty(t, out.Stack)
+	assert.Contains(t, string(h.Out), `"stack_trace":[]`)
+
+	logger.SetStackTrace(log.StackConfig{Depth: 1})
+	stackHelper(logger)
+	var str struct {
+		Stack string `json:"stack_trace"`
+	}
+	assert.NoError(t, json.Unmarshal(h.Out, &str))
+	assert.Equal(t, 1, strings.Count(str.Stack, "Function: "))
+	assert.Contains(t, str.Stack, "Function: github.com/jasonsoft/log/v2_test.stackHelper")
+}
+
+// pkgFrame and pkgStackTrace have the shape of the stack trace of github.com/pkg/errors
+type pkgFrame uintptr
+
+type pkgStackTrace []pkgFrame
+
+type pkgError struct {
+	stack []uintptr
+}
+
+func (e *pkgError) Error() string {
+	return "pkg error"
+}
+
+func (e *pkgError) StackTrace() pkgStackTrace {
+	frames := make(pkgStackTrace, len(e.stack))
+	for i, pc := range e.stack {
+		frames[i] = pkgFrame(pc)
+	}
+	return frames
+}
+
+type framerError struct {
+	err   error
+	stack []uintptr
+}
+
+func (e *framerError) Error() string {
+	return "framer: " + e.err.Error()
+}
+
+func (e *framerError) Unwrap() error {
+	return e.err
+}
+
+func (e *framerError) Frames() []uintptr {
+	return e.stack
+}
+
+func callers() []uintptr {
+	pcs := make([]uintptr, 32)
+	n := runtime.Callers(2, pcs)
+	return pcs[:n]
+}
+
+func newPkgError() error {
+	return &pkgError{stack: callers()}
+}
+
+func newFramerError() error {
+	return &framerError{err: errors.New("origin"), stack: callers()}
+}
+
+func TestErrorStackTrace(t *testing.T) {
+	logger := log.New()
+	h := memory.New()
+	logger.AddHandler(h, log.AllLevels...)
+	logger.SetStackTrace(log.StackConfig{Frames: true, Depth: 1})
+
+	var out struct {
+		Stack []stackFrame `json:"stack_trace"`
+	}
+
+	logger.Err(newFramerError()).Error("framer")
+	assert.NoError(t, json.Unmarshal(h.Out, &out))
+	assert.Len(t, out.Stack, 1)
+	assert.Equal(t, "github.com/jasonsoft/log/v2_test.newFramerError", out.Stack[0].Func)
+
+	// the innermost stack of the chain is used
+	wrapped := &framerError{err: newPkgError(), stack: callers()}
+	logger.Err(wrapped).StackTrace().Info("pkg")
+	assert.NoError(t, json.Unmarshal(h.Out, &out))
+	assert.Len(t, out.Stack, 1)
+	assert.Equal(t, "github.com/jasonsoft/log/v2_test.newPkgError", out.Stack[0].Func)
+
+	// errors without a stack use the stack of the logging call
+	logger.Err(errors.New("no stack")).StackTrace().Info("plain")
+	assert.NoError(t, json.Unmarshal(h.Out, &out))
+	assert.Len(t, out.Stack, 1)
+	assert.Equal(t, "github.com/jasonsoft/log/v2_test.TestErrorStackTrace", out.Stack[0].Func)
+}
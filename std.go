@@ -0,0 +1,165 @@
+package log
+
+import (
+	"bytes"
+	"context"
+	"io"
+	stdlog "log"
+	"sync"
+	"sync/atomic"
+)
+
+// fallbackLogger prints the errors of the logger when ErrorHandler isn't set while the standard
+// logger is redirected by RedirectStdLog, so the errors don't loop back.
+var fallbackLogger atomic.Value // fallbackHolder
+
+// fallbackHolder wraps the fallback logger, which is nil when the standard logger isn't redirected
+type fallbackHolder struct {
+	logger *stdlog.Logger
+}
+
+func init() {
+	fallbackLogger.Store(fallbackHolder{})
+}
+
+func printError(format string, v ...interface{}) {
+	if logger := fallbackLogger.Load().(fallbackHolder).logger; logger != nil {
+		logger.Printf(format, v...)
+		return
+	}
+	stdlog.Printf(format, v...)
+}
+
+// ReportError passes the error to ErrorHandler, or prints it with the format by the standard logger.
+// Handlers use it for the errors which can't be returned, e.g. the errors of background writes.
+// The errors don't loop back into the logger while the standard logger is redirected by RedirectStdLog.
+func ReportError(format string, err error) {
+	if ErrorHandler != nil {
+		ErrorHandler(err)
+	} else {
+		printError(format, err)
+	}
+}
+
+type writer struct {
+	context    Context
+	level      Level
+	callerSkip int
+
+	mu      sync.Mutex
+	partial []byte // the line which isn't ended by a newline yet
+}
+
+// Writer returns an io.WriteCloser which writes each line as an entry of the level with the fields
+// of the context.  PanicLevel and FatalLevel entries are written without panicking or exiting.
+// A line which isn't ended by a newline is kept until the rest of the line is written, so
+// Close writes the last line if it doesn't end with a newline.
+func (c Context) Writer(level Level) io.WriteCloser {
+	return &writer{context: c, level: level}
+}
+
+// Writer returns an io.WriteCloser which writes each line as an entry of the level
+func (l *Logger) Writer(level Level) io.WriteCloser {
+	return newContext(l).Writer(level)
+}
+
+// Writer returns an io.WriteCloser which writes each line as an entry of the level by the default logger
+func Writer(level Level) io.WriteCloser {
+	return _logger.Writer(level)
+}
+
+// NewStdLogger returns a standard library logger which writes each message as an entry of the level
+// with the fields of Ctx(ctx), e.g. for http.Server.ErrorLog
+func (l *Logger) NewStdLogger(level Level, ctx context.Context) *stdlog.Logger {
+	return stdlog.New(l.stdWriter(level, ctx), "", 0)
+}
+
+// NewStdLogger returns a standard library logger which writes each message by the default logger
+func NewStdLogger(level Level, ctx context.Context) *stdlog.Logger {
+	return _logger.NewStdLogger(level, ctx)
+}
+
+// RedirectStdLog redirects the output of the global standard library logger to the logger, and
+// it returns a func which restores the standard library logger.  The errors of the logger are
+// printed by the original output of the standard library logger while it is redirected.
+func (l *Logger) RedirectStdLog(level Level) func() {
+	output, prefix, flags := stdlog.Writer(), stdlog.Prefix(), stdlog.Flags()
+	original := stdlog.New(output, prefix, flags)
+
+	fallbackLogger.Store(fallbackHolder{original})
+	stdlog.SetOutput(l.stdWriter(level, context.Background()))
+	stdlog.SetPrefix("")
+	stdlog.SetFlags(0)
+
+	return func() {
+		stdlog.SetOutput(output)
+		stdlog.SetPrefix(prefix)
+		stdlog.SetFlags(flags)
+		fallbackLogger.Store(fallbackHolder{})
+	}
+}
+
+// RedirectStdLog redirects the output of the global standard library logger to the default logger
+func RedirectStdLog(level Level) func() {
+	return _logger.RedirectStdLog(level)
+}
+
+func (l *Logger) stdWriter(level Level, ctx context.Context) io.Writer {
+	return &writer{
+		context: l.Ctx(ctx),
+		level:   level,
+		// frames: the output func of the standard library logger and its Print func
+		callerSkip: 2,
+	}
+}
+
+// Write implements io.Writer.  Empty lines are ignored.
+func (w *writer) Write(p []byte) (int, error) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	rest := p
+	for {
+		idx := bytes.IndexByte(rest, '\n')
+		if idx < 0 {
+			w.partial = append(w.partial, rest...)
+			return len(p), nil
+		}
+
+		line := rest[:idx]
+		if len(w.partial) > 0 {
+			w.partial = append(w.partial, line...)
+			line = w.partial
+		}
+		w.writeLine(line)
+		w.partial = w.partial[:0]
+		rest = rest[idx+1:]
+	}
+}
+
+// Close implements io.Closer.  It writes the line which isn't ended by a newline.
+func (w *writer) Close() error {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	w.writeLine(w.partial)
+	w.partial = w.partial[:0]
+	return nil
+}
+
+func (w *writer) writeLine(line []byte) {
+	line = bytes.TrimRight(line, "\r")
+	if len(line) == 0 {
+		return
+	}
+
+	e := newEntry(w.context.logger, w.context.buf)
+	e.name = w.context.name
+	e.span = w.context.span
+	e.errStack = w.context.errStack
+	// frames: writeLine and Write or Close
+	e.callerSkip = 1 + w.callerSkip + w.context.callerSkip
+	e.Level = w.level
+	e.Message = string(line)
+	handler(e)
+}
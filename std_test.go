@@ -0,0 +1,87 @@
+package log_test
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	stdlog "log"
+	"os"
+	"testing"
+
+	"github.com/jasonsoft/log/v2"
+	"github.com/jasonsoft/log/v2/handlers/memory"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestWriter(t *testing.T) {
+	logger := log.New()
+	h := memory.New()
+	logger.AddHandler(h, log.AllLevels...)
+
+	w := logger.Str("app", "santa").Writer(log.WarnLevel)
+	n, err := io.WriteString(w, "first\r\n\nsecond\n")
+	assert.NoError(t, err)
+	assert.Equal(t, 15, n)
+	assert.Equal(t, `{"app":"santa","level":"WARN","msg":"second"}`+"\n", string(h.Out))
+
+	// the writer never panics
+	_, _ = logger.Writer(log.PanicLevel).Write([]byte("panic\n"))
+	assert.Equal(t, `{"level":"PANIC","msg":"panic"}`+"\n", string(h.Out))
+}
+
+func TestWriterPartialLines(t *testing.T) {
+	logger := log.New()
+	h := memory.New()
+	logger.AddHandler(h, log.AllLevels...)
+
+	w := logger.Writer(log.InfoLevel)
+	_, _ = fmt.Fprint(w, "hello wo")
+	assert.Equal(t, "", string(h.Out))
+
+	_, _ = fmt.Fprint(w, "rld\nsecond ")
+	assert.Equal(t, `{"level":"INFO","msg":"hello world"}`+"\n", string(h.Out))
+
+	_, _ = fmt.Fprint(w, "line")
+	assert.NoError(t, w.Close())
+	assert.Equal(t, `{"level":"INFO","msg":"second line"}`+"\n", string(h.Out))
+}
+
+func TestNewStdLogger(t *testing.T) {
+	logger := log.New()
+	h := memory.New()
+	logger.AddHandler(h, log.AllLevels...)
+	logger.SetCaller(log.CallerConfig{Enabled: true})
+
+	ctx := logger.Str("request_id", "abc").WithContext(context.Background())
+	std := logger.NewStdLogger(log.ErrorLevel, ctx)
+
+	std.Printf("http: TLS handshake error from %s", "10.0.0.1")
+	l := line() - 1
+	assert.Contains(t, string(h.Out), `{"request_id":"abc","caller":`)
+	assert.Contains(t, string(h.Out), fmt.Sprintf(`/std_test.go:%d"`, l))
+	assert.Contains(t, string(h.Out), `"level":"ERROR","msg":"http: TLS handshake error from 10.0.0.1"`)
+}
+
+func TestRedirectStdLog(t *testing.T) {
+	logger := log.New()
+	h := memory.New()
+	logger.AddHandler(h, log.AllLevels...)
+
+	var original bytes.Buffer
+	stdlog.SetOutput(&original)
+	defer stdlog.SetOutput(os.Stderr)
+	defer func(fn func(err error)) { log.ErrorHandler = fn }(log.ErrorHandler)
+	log.ErrorHandler = nil
+
+	restore := logger.RedirectStdLog(log.InfoLevel)
+	stdlog.Print("from stdlib")
+	// the errors of handlers are printed by the original output instead of the logger
+	log.ReportError("handler failed: %v", errors.New("oops"))
+	restore()
+
+	assert.Equal(t, `{"level":"INFO","msg":"from stdlib"}`+"\n", string(h.Out))
+	assert.Contains(t, original.String(), "handler failed: oops")
+	assert.Equal(t, stdlog.LstdFlags, stdlog.Flags())
+}
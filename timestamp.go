@@ -0,0 +1,72 @@
+package log
+
+import (
+	"time"
+)
+
+// Time formats which are supported besides the layouts of the time package
+const (
+	// TimeFormatUnix formats the time as seconds since epoch
+	TimeFormatUnix = "UNIX"
+	// TimeFormatUnixMs formats the time as milliseconds since epoch
+	TimeFormatUnixMs = "UNIXMS"
+	// TimeFormatUnixMicro formats the time as microseconds since epoch
+	TimeFormatUnixMicro = "UNIXMICRO"
+)
+
+// TimestampConfig configures the timestamp field of entries
+type TimestampConfig struct {
+	// Enabled adds the timestamp field to every entry
+	Enabled bool
+	// Format is a layout of the time package, or TimeFormatUnix, TimeFormatUnixMs and TimeFormatUnixMicro.
+	// Default: time.RFC3339Nano
+	Format string
+	// Location is the time zone of the timestamp. Default: UTC
+	Location *time.Location
+}
+
+// Clock provides the current time of the logger, so tests can use a fixed time
+type Clock interface {
+	Now() time.Time
+}
+
+type systemClock struct{}
+
+func (systemClock) Now() time.Time {
+	return time.Now()
+}
+
+// SetTimestamp configures the timestamp field of entries.  The timestamp field is disabled by default.
+func (l *Logger) SetTimestamp(config TimestampConfig) {
+	switch config.Format {
+	case "":
+		config.Format = time.RFC3339Nano
+	case TimeFormatUnix:
+		config.Format = "" // the encoder uses empty format for unix time
+	}
+	if config.Location == nil {
+		config.Location = time.UTC
+	}
+	l.timestamp.Store(config)
+}
+
+func (l *Logger) timestampConfig() TimestampConfig {
+	return l.timestamp.Load().(TimestampConfig)
+}
+
+// SetClock sets the clock which is used by the timestamp field and Trace.  A nil clock resets to the system clock.
+func (l *Logger) SetClock(clock Clock) {
+	if clock == nil {
+		clock = systemClock{}
+	}
+	l.clock.Store(clockHolder{clock})
+}
+
+func (l *Logger) now() time.Time {
+	return l.clock.Load().(clockHolder).Clock.Now()
+}
+
+// clockHolder keeps the same concrete type in atomic.Value
+type clockHolder struct {
+	Clock
+}
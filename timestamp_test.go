@@ -0,0 +1,53 @@
+package log_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/jasonsoft/log/v2"
+	"github.com/jasonsoft/log/v2/handlers/memory"
+	"github.com/stretchr/testify/assert"
+)
+
+type fixedClock struct {
+	t time.Time
+}
+
+func (c fixedClock) Now() time.Time {
+	return c.t
+}
+
+func TestTimestamp(t *testing.T) {
+	logger := log.New()
+	h := memory.New()
+	logger.AddHandler(h, log.AllLevels...)
+
+	now := time.Date(2020, 8, 26, 10, 20, 30, 123456789, time.UTC)
+	logger.SetClock(fixedClock{t: now})
+
+	logger.Info("disabled")
+	assert.Equal(t, `{"level":"INFO","msg":"disabled"}`+"\n", string(h.Out))
+
+	logger.SetTimestamp(log.TimestampConfig{Enabled: true})
+	logger.Str("a", "b").Info("default")
+	assert.Equal(t, `{"a":"b","time":"2020-08-26T10:20:30.123456789Z","level":"INFO","msg":"default"}`+"\n", string(h.Out))
+
+	taipei := time.FixedZone("Asia/Taipei", 8*60*60)
+	logger.SetFieldNames(log.FieldNames{Time: "ts"})
+	logger.SetTimestamp(log.TimestampConfig{Enabled: true, Format: time.RFC3339, Location: taipei})
+	logger.Info("location")
+	assert.Equal(t, `{"ts":"2020-08-26T18:20:30+08:00","level":"INFO","msg":"location"}`+"\n", string(h.Out))
+
+	logger.SetFieldNames(log.FieldNames{})
+	logger.SetTimestamp(log.TimestampConfig{Enabled: true, Format: log.TimeFormatUnix})
+	logger.Info("unix")
+	assert.Equal(t, `{"time":1598437230,"level":"INFO","msg":"unix"}`+"\n", string(h.Out))
+
+	logger.SetTimestamp(log.TimestampConfig{Enabled: true, Format: log.TimeFormatUnixMs})
+	logger.Info("unix ms")
+	assert.Equal(t, `{"time":1598437230123,"level":"INFO","msg":"unix ms"}`+"\n", string(h.Out))
+
+	logger.SetTimestamp(log.TimestampConfig{Enabled: true, Format: log.TimeFormatUnixMicro})
+	logger.Info("unix micro")
+	assert.Equal(t, `{"time":1598437230123456,"level":"INFO","msg":"unix micro"}`+"\n", string(h.Out))
+}
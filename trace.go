@@ -0,0 +1,149 @@
+package log
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"fmt"
+)
+
+// SpanContext identifies a span of distributed tracing, see https://www.w3.org/TR/trace-context/
+type SpanContext struct {
+	TraceID    [16]byte
+	SpanID     [8]byte
+	TraceFlags byte
+}
+
+// IsValid reports whether the trace ID and span ID aren't zero
+func (sc SpanContext) IsValid() bool {
+	return sc.TraceID != [16]byte{} && sc.SpanID != [8]byte{}
+}
+
+// Traceparent returns the traceparent header of the span context, e.g.
+// "00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01"
+func (sc SpanContext) Traceparent() string {
+	return fmt.Sprintf("00-%x-%x-%02x", sc.TraceID, sc.SpanID, sc.TraceFlags)
+}
+
+// ParseTraceparent parses the W3C traceparent header
+func ParseTraceparent(traceparent string) (SpanContext, error) {
+	var sc SpanContext
+	if len(traceparent) < 55 || traceparent[2] != '-' || traceparent[35] != '-' || traceparent[52] != '-' {
+		return sc, fmt.Errorf("log: invalid traceparent %q", traceparent)
+	}
+	if traceparent[:2] == "ff" || (traceparent[:2] == "00" && len(traceparent) != 55) ||
+		(len(traceparent) > 55 && traceparent[55] != '-') {
+		return sc, fmt.Errorf("log: invalid traceparent %q", traceparent)
+	}
+
+	var flags [1]byte
+	_, err1 := hex.Decode(sc.TraceID[:], []byte(traceparent[3:35]))
+	_, err2 := hex.Decode(sc.SpanID[:], []byte(traceparent[36:52]))
+	_, err3 := hex.Decode(flags[:], []byte(traceparent[53:55]))
+	if err1 != nil || err2 != nil || err3 != nil || !sc.IsValid() {
+		return SpanContext{}, fmt.Errorf("log: invalid traceparent %q", traceparent)
+	}
+	sc.TraceFlags = flags[0]
+	return sc, nil
+}
+
+// TraceProvider supplies the span context of ctx, so OpenTelemetry or a local tracer can be used
+type TraceProvider interface {
+	SpanContext(ctx context.Context) (SpanContext, bool)
+}
+
+// TracingConfig configures the trace fields which are added by Ctx
+type TracingConfig struct {
+	// Provider supplies the span context.  Default: the span context stored by ContextWithSpanContext
+	Provider TraceProvider
+	// ChildSpan starts a child span in Trace, so the completion entry of Stop has its own
+	// span ID and the parent_span_id field
+	ChildSpan bool
+}
+
+type spanKeyType int
+
+const spanKey spanKeyType = 0
+
+// ContextWithSpanContext returns a copy of ctx which carries the span context
+func ContextWithSpanContext(ctx context.Context, sc SpanContext) context.Context {
+	return context.WithValue(ctx, spanKey, sc)
+}
+
+// ContextWithTraceparent returns a copy of ctx which carries the span context of the traceparent header
+func ContextWithTraceparent(ctx context.Context, traceparent string) (context.Context, error) {
+	sc, err := ParseTraceparent(traceparent)
+	if err != nil {
+		return ctx, err
+	}
+	return ContextWithSpanContext(ctx, sc), nil
+}
+
+type contextTraceProvider struct{}
+
+func (contextTraceProvider) SpanContext(ctx context.Context) (SpanContext, bool) {
+	sc, ok := ctx.Value(spanKey).(SpanContext)
+	return sc, ok && sc.IsValid()
+}
+
+// SetTracing configures the trace fields which are added by Ctx
+func (l *Logger) SetTracing(config TracingConfig) {
+	if config.Provider == nil {
+		config.Provider = contextTraceProvider{}
+	}
+	l.tracing.Store(config)
+}
+
+func (l *Logger) tracingConfig() TracingConfig {
+	return l.tracing.Load().(TracingConfig)
+}
+
+// SetTracing configures the trace fields which are added by Ctx of the default logger
+func SetTracing(config TracingConfig) {
+	_logger.SetTracing(config)
+}
+
+// Trace returns a new entry of the context with a Stop method to fire off
+// a corresponding completion log, useful with defer.
+func (c Context) Trace(msg string) *Entry {
+	e := c.newEntry()
+	// the entry is written by Stop, which is called by the caller directly
+	e.callerSkip = c.callerSkip
+	return e.Trace(msg)
+}
+
+// startChildSpan replaces the span ID of the entry with a new span ID
+func (e *Entry) startChildSpan() {
+	if !e.span.IsValid() || !e.logger.tracingConfig().ChildSpan {
+		return
+	}
+	e.parentSpan = e.span.SpanID
+	for e.span.SpanID == e.parentSpan || e.span.SpanID == [8]byte{} {
+		_, _ = rand.Read(e.span.SpanID[:])
+	}
+}
+
+func (e *Entry) appendSpan(names FieldNames) {
+	if !e.span.IsValid() {
+		return
+	}
+
+	var buf [32]byte
+	hex.Encode(buf[:], e.span.TraceID[:])
+	e.buf = enc.AppendKey(e.buf, names.TraceID)
+	e.buf = enc.AppendString(e.buf, string(buf[:32]))
+
+	hex.Encode(buf[:], e.span.SpanID[:])
+	e.buf = enc.AppendKey(e.buf, names.SpanID)
+	e.buf = enc.AppendString(e.buf, string(buf[:16]))
+
+	if e.parentSpan != [8]byte{} {
+		hex.Encode(buf[:], e.parentSpan[:])
+		e.buf = enc.AppendKey(e.buf, names.ParentSpanID)
+		e.buf = enc.AppendString(e.buf, string(buf[:16]))
+	}
+
+	hex.Encode(buf[:], []byte{e.span.TraceFlags})
+	e.buf = enc.AppendKey(e.buf, names.TraceFlags)
+	e.buf = enc.AppendString(e.buf, string(buf[:2]))
+}
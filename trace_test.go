@@ -0,0 +1,88 @@
+package log_test
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/jasonsoft/log/v2"
+	"github.com/jasonsoft/log/v2/handlers/memory"
+	"github.com/stretchr/testify/assert"
+)
+
+const traceparent = "00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01"
+
+func TestParseTraceparent(t *testing.T) {
+	sc, err := log.ParseTraceparent(traceparent)
+	assert.NoError(t, err)
+	assert.True(t, sc.IsValid())
+	assert.Equal(t, byte(1), sc.TraceFlags)
+	assert.Equal(t, traceparent, sc.Traceparent())
+
+	for _, s := range []string{
+		"",
+		"00-00000000000000000000000000000000-00f067aa0ba902b7-01",
+		"00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01-extra",
+		"ff-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01",
+		"00-4bf92f3577b34da6a3ce929d0e0e473x-00f067aa0ba902b7-01",
+	} {
+		_, err := log.ParseTraceparent(s)
+		assert.Error(t, err, s)
+	}
+}
+
+func TestTraceFields(t *testing.T) {
+	logger := log.New()
+	h := memory.New()
+	logger.AddHandler(h, log.AllLevels...)
+
+	ctx, err := log.ContextWithTraceparent(context.Background(), traceparent)
+	assert.NoError(t, err)
+
+	logger.Ctx(ctx).Str("a", "b").Info("hello")
+	assert.Equal(t, `{"a":"b","trace_id":"4bf92f3577b34da6a3ce929d0e0e4736","span_id":"00f067aa0ba902b7","trace_flags":"01","level":"INFO","msg":"hello"}`+"\n", string(h.Out))
+
+	logger.Ctx(context.Background()).Info("no trace")
+	assert.Equal(t, `{"level":"INFO","msg":"no trace"}`+"\n", string(h.Out))
+}
+
+type staticProvider struct {
+	sc log.SpanContext
+}
+
+func (p staticProvider) SpanContext(ctx context.Context) (log.SpanContext, bool) {
+	return p.sc, true
+}
+
+func TestTraceProvider(t *testing.T) {
+	logger := log.New()
+	h := memory.New()
+	logger.AddHandler(h, log.AllLevels...)
+
+	sc, _ := log.ParseTraceparent(traceparent)
+	logger.SetTracing(log.TracingConfig{Provider: staticProvider{sc: sc}})
+
+	logger.Ctx(context.Background()).Info("hello")
+	assert.Contains(t, string(h.Out), `"trace_id":"4bf92f3577b34da6a3ce929d0e0e4736"`)
+}
+
+func TestTraceChildSpan(t *testing.T) {
+	logger := log.New()
+	h := memory.New()
+	logger.AddHandler(h, log.AllLevels...)
+	logger.SetTracing(log.TracingConfig{ChildSpan: true})
+	logger.SetClock(fixedClock{})
+
+	ctx, _ := log.ContextWithTraceparent(context.Background(), traceparent)
+	logger.Ctx(ctx).Trace("query").Stop()
+
+	out := string(h.Out)
+	assert.True(t, strings.HasPrefix(out, `{"duration":0,"trace_id":"4bf92f3577b34da6a3ce929d0e0e4736","span_id":"`), out)
+	assert.NotContains(t, out, `"span_id":"00f067aa0ba902b7"`)
+	assert.Contains(t, out, `"parent_span_id":"00f067aa0ba902b7","trace_flags":"01","level":"INFO","msg":"query"}`)
+
+	// without child spans, Trace logs the span of ctx
+	logger.SetTracing(log.TracingConfig{})
+	logger.Ctx(ctx).Trace("query").Stop()
+	assert.Contains(t, string(h.Out), `"span_id":"00f067aa0ba902b7","trace_flags":"01"`)
+}